@@ -34,13 +34,10 @@ func main() {
 
 	go tick(ch)
 
-	ticker := time.NewTicker(1000 / 60 * time.Millisecond)
-	for !window.ShouldClose() {
-		<-ticker.C
-		x--
+	graphics.Run(func(dt float64) {
+		x -= float32(60 * dt)
 		ro.UpdatePointers()
-		graphics.Render()
-	}
+	}, nil)
 }
 
 func tick(ch chan graphics.WaitSignal) {
@@ -0,0 +1,108 @@
+package debug
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lucas-s-work/gopengl2/graphics"
+	"github.com/lucas-s-work/gopengl2/graphics/text"
+)
+
+/*
+On screen frame statistics for the default renderer. The three lines are rewritten by a single
+render job at an interval rather than every frame, the renderer runs one job per frame. The
+default font is upper case only.
+*/
+
+const DefaultOverlayInterval = 250 * time.Millisecond
+
+type Overlay struct {
+	fps, timings text.Text
+	counters     text.Text
+	x, y         int
+	visible      bool
+	lastUpdate   time.Time
+	Interval     time.Duration
+}
+
+// x, y is the bottom left of the overlay in canvas pixels
+func CreateOverlay(x, y int, font *text.Font) *Overlay {
+	o := &Overlay{
+		x:        x,
+		y:        y,
+		visible:  true,
+		Interval: DefaultOverlayInterval,
+	}
+
+	o.counters = text.CreateText("", x, y, font)
+	o.timings = text.CreateText("", x, y+16, font)
+	o.fps = text.CreateText("", x, y+32, font)
+	o.counters.R.SetLayer(graphics.LayerUI)
+	o.timings.R.SetLayer(graphics.LayerUI)
+	o.fps.R.SetLayer(graphics.LayerUI)
+
+	return o
+}
+
+// Call once per frame, e.g from the loop's render func
+func (o *Overlay) Update() {
+	if !o.visible || time.Since(o.lastUpdate) < o.Interval {
+		return
+	}
+	stats := graphics.FrameStats()
+	last := stats.Last
+
+	gpu := "N/A"
+	if last.GPUValid {
+		gpu = ms(last.GPU)
+	}
+
+	fps := fmt.Sprintf("FPS %.1f AVG %s P50 %s P95 %s P99 %s MAX %s",
+		stats.FPS, ms(stats.Average), ms(stats.P50), ms(stats.P95), ms(stats.P99), ms(stats.Max))
+	timings := fmt.Sprintf("CPU %s JOBS %s CULL %s UNIF %s DRAW %s GPU %s",
+		ms(last.CPU), ms(last.Jobs), ms(last.Culling), ms(last.Uniforms), ms(last.Draw), gpu)
+
+	counts := graphics.DefaultRenderer().Window().DrawStats()
+	counters := fmt.Sprintf("DRAWS %d VERTS %d PROGRAMS %d TEXTURES %d UPLOADS %d %dKB UNIFORMS %d",
+		counts.DrawCalls, counts.Vertices, counts.ProgramSwitches, counts.TextureBinds,
+		counts.BufferUploads, counts.BufferBytes/1024, counts.UniformUploads)
+
+	queued := graphics.AddJobBlock(o.fps.R, func(graphics.RenderObject) {
+		o.fps.SetText(fps, o.x, o.y+32)
+		o.timings.SetText(timings, o.x, o.y+16)
+		o.counters.SetText(counters, o.x, o.y)
+	})
+
+	// Try again next frame if the job queue was full
+	if queued {
+		o.lastUpdate = time.Now()
+	}
+}
+
+func (o *Overlay) SetVisible(visible bool) {
+	o.visible = visible
+	o.fps.R.SetVisible(visible)
+	o.timings.R.SetVisible(visible)
+	o.counters.R.SetVisible(visible)
+
+	// Refresh straight away when shown again
+	o.lastUpdate = time.Time{}
+}
+
+func (o *Overlay) Toggle() {
+	o.SetVisible(!o.visible)
+}
+
+func (o *Overlay) Visible() bool {
+	return o.visible
+}
+
+func (o *Overlay) Delete() {
+	o.fps.R.Delete()
+	o.timings.R.Delete()
+	o.counters.R.Delete()
+}
+
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.2fMS", float64(d)/float64(time.Millisecond))
+}
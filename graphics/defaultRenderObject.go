@@ -1,37 +1,16 @@
 package graphics
 
 import (
+	"image/color"
+	"time"
+
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/lucas-s-work/gopengl2/graphics/opengl"
 )
 
 type DefaultRenderObject struct {
 	*BaseRenderObject
 }
 
-func CreateDefaultRenderObject(texture string, elements int) *DefaultRenderObject {
-	vao := opengl.CreateDefaultVao(window, texture, elements)
-
-	baseRo := &BaseRenderObject{
-		vao,
-		0,
-		vao.GetBuffer("vert"),
-		vao.GetBuffer("verttexcoord"),
-		true,
-		false,
-		make(chan WaitSignal),
-		false,
-		false,
-		false,
-	}
-
-	ro := &DefaultRenderObject{baseRo}
-
-	renderObjects = append(renderObjects, ro)
-
-	return ro
-}
-
 func (ro *DefaultRenderObject) CreateRect(x, y, width, height, texX, texY, texWidth, texHeight int) int {
 	index := ro.freeVert
 	ro.freeVert += 6
@@ -40,7 +19,26 @@ func (ro *DefaultRenderObject) CreateRect(x, y, width, height, texX, texY, texWi
 	return index
 }
 
+func (ro *DefaultRenderObject) CreateRectColor(x, y, width, height, texX, texY, texWidth, texHeight int, c color.RGBA) int {
+	index := ro.CreateRect(x, y, width, height, texX, texY, texWidth, texHeight)
+	ro.SetRectColor(index, c)
+
+	return index
+}
+
 func (ro *DefaultRenderObject) ModifyRect(index, x, y, width, height, texX, texY, texWidth, texHeight int) {
+	// Keep any rotation pivot relative to the rect as it moves, without a rotation buffer nothing
+	// is rotated so there is no pivot to keep
+	if ro.rBuff != nil {
+		dx := float32(x) - ro.vBuff.Elements[index*2]
+		dy := float32(y) - ro.vBuff.Elements[index*2+1]
+		for i := index; i < index+6; i++ {
+			ro.rBuff.Elements[i*4] += dx
+			ro.rBuff.Elements[i*4+1] += dy
+		}
+		ro.rBuff.MarkChanged()
+	}
+
 	ro.SetVertex(index, x, y, texX, texY+texHeight)
 	ro.SetVertex(index+1, x+width, y, texX+texWidth, texY+texHeight)
 	ro.SetVertex(index+2, x, y+height, texX, texY)
@@ -49,6 +47,29 @@ func (ro *DefaultRenderObject) ModifyRect(index, x, y, width, height, texX, texY
 	ro.SetVertex(index+5, x+width, y, texX+texWidth, texY+texHeight)
 }
 
+// Tint every vertex of the rect, alpha fades the rect out
+func (ro *DefaultRenderObject) SetRectColor(index int, c color.RGBA) {
+	for i := 0; i < 6; i++ {
+		ro.SetVertexColor(index+i, c)
+	}
+}
+
+// Rotate the rect around its own centre
+func (ro *DefaultRenderObject) SetRectRotation(index int, angle float32) {
+	// Vertex 0 is the bottom left corner and vertex 4 the top right
+	cx := (ro.vBuff.Elements[index*2] + ro.vBuff.Elements[(index+4)*2]) / 2
+	cy := (ro.vBuff.Elements[index*2+1] + ro.vBuff.Elements[(index+4)*2+1]) / 2
+
+	ro.SetRectRotationPivot(index, angle, cx, cy)
+}
+
+// Rotate the rect around a pivot in pixel coordinates, the pivot follows the rect in ModifyRect
+func (ro *DefaultRenderObject) SetRectRotationPivot(index int, angle, pivotX, pivotY float32) {
+	for i := 0; i < 6; i++ {
+		ro.SetVertexRotation(index+i, pivotX, pivotY, angle)
+	}
+}
+
 func (ro *DefaultRenderObject) RemoveSquare(index int) {
 	ro.ModifyRect(index, 0, 0, 0, 0, 0, 0, 0, 0)
 }
@@ -57,6 +78,10 @@ func (ro *DefaultRenderObject) CreateSquare(x, y, width, texX, texY, texWidth in
 	return ro.CreateRect(x, y, width, width, texX, texY, texWidth, texWidth)
 }
 
+func (ro *DefaultRenderObject) CreateSquareColor(x, y, width, texX, texY, texWidth int, c color.RGBA) int {
+	return ro.CreateRectColor(x, y, width, width, texX, texY, texWidth, texWidth, c)
+}
+
 func (ro *DefaultRenderObject) ModifySquare(index, x, y, width, texX, texY, texWidth int) {
 	ro.ModifyRect(index, x, y, width, width, texX, texY, texWidth, texWidth)
 }
@@ -69,6 +94,64 @@ func (ro *DefaultRenderObject) SetCam(x, y *float32) {
 	ro.vao.SetCam(x, y)
 }
 
+func (ro *DefaultRenderObject) SetRotation(angle *float32) {
+	ro.vao.SetRotation(angle)
+}
+
+func (ro *DefaultRenderObject) SetRotationCenter(x, y *float32) {
+	ro.vao.SetRotationCenter(x, y)
+}
+
+func (ro *DefaultRenderObject) SetScale(x, y *float32) {
+	ro.vao.SetScale(x, y)
+}
+
+func (ro *DefaultRenderObject) SetZoom(zoom *float32) {
+	ro.vao.SetZoom(zoom)
+}
+
+func (ro *DefaultRenderObject) SetTransform(transform mgl32.Mat3) {
+	ro.vao.SetTransform(transform)
+}
+
+func (ro *DefaultRenderObject) Transform() mgl32.Mat3 {
+	return ro.vao.Transform()
+}
+
+func (ro *DefaultRenderObject) Translate(x, y float32) {
+	ro.vao.Translate(x, y)
+}
+
+func (ro *DefaultRenderObject) Rotate(angle float32) {
+	ro.vao.Rotate(angle)
+}
+
+func (ro *DefaultRenderObject) Scale(x, y float32) {
+	ro.vao.Scale(x, y)
+}
+
+func (ro *DefaultRenderObject) Skew(kx, ky float32) {
+	ro.vao.Skew(kx, ky)
+}
+
+func (ro *DefaultRenderObject) SetParentTransform(parent mgl32.Mat3) {
+	ro.vao.SetParentTransform(parent)
+}
+
+func (ro *DefaultRenderObject) SetView(view mgl32.Mat3) {
+	ro.vao.SetView(view)
+}
+
+// Mouse position after this object's cam and zoom, as used for SetTranslation
+func (ro *DefaultRenderObject) MouseWorld() (float32, float32) {
+	return ro.vao.CanvasToWorld(ro.renderer.window.MousePosition())
+}
+
+// Mouse position in this object's vertex coordinates, as used for CreateRect
+func (ro *DefaultRenderObject) MouseLocal() (float32, float32) {
+	return ro.vao.CanvasToLocal(ro.renderer.window.MousePosition())
+}
+
 func (ro *DefaultRenderObject) SetRenderBounds(x, y, width, height float32) {
 	ro.vao.SetRenderBounds(mgl32.Vec4{x, y, width, height})
 }
@@ -78,17 +161,28 @@ func (ro *DefaultRenderObject) Render() {
 		<-ro.waitChan
 	}
 	// We should still prepare to render, this updates any variables etc
+	start := time.Now()
 	ro.PrepRender()
-	if ro.CanRender() {
+	prepped := time.Now()
+	visible := ro.CanRender()
+	culled := time.Now()
+	if visible {
 		ro.vao.PrepRender()
 		ro.vao.Render()
 	}
+
+	ro.renderer.timer.addObject(prepped.Sub(start), culled.Sub(prepped), time.Since(culled))
 }
 
 func (ro *DefaultRenderObject) CanRender() bool {
-	return ro.ShouldRender && ro.vao.ShouldRender()
+	return ro.visible() && ro.vao.ShouldRender()
 }
 
 func (ro *DefaultRenderObject) UpdatePointers() {
 	ro.vao.UpdatePointers()
 }
+
+// Re-upload every uniform, only needed after setting them with GL directly
+func (ro *DefaultRenderObject) RefreshUniforms() {
+	ro.vao.RefreshUniforms()
+}
@@ -6,24 +6,55 @@ import (
 )
 
 /*
-All Render Objects are stored here and rendered here, on creation they are added and rendered
-without additional work.
+Package level functions operate on a default Renderer created by Init, use NewRenderer
+for additional independent scenes.
 */
 
 var (
-	window        *opengl.Window
-	renderObjects []RenderObject
+	defaultRenderer *Renderer
+	defaultLoop     *Loop
 )
 
 func Init(w *opengl.Window) {
 	opengl.GlInit()
-	window = w
+	defaultRenderer = NewRenderer(w)
+	defaultLoop = NewLoop(defaultRenderer)
+}
+
+func DefaultRenderer() *Renderer {
+	return defaultRenderer
+}
+
+func Root() *Node {
+	return defaultRenderer.Root()
+}
+
+func CreateDefaultRenderObject(texture string, elements int) *DefaultRenderObject {
+	return defaultRenderer.CreateDefaultRenderObject(texture, elements)
+}
+
+func CreateBaseRenderObject(texture string, elements int) *BaseRenderObject {
+	return defaultRenderer.CreateBaseRenderObject(texture, elements)
 }
 
 func DeleteRenderObjects() {
-	for _, ro := range renderObjects {
-		ro.Delete()
-	}
+	defaultRenderer.DeleteRenderObjects()
+}
+
+func Remove(ro RenderObject) {
+	defaultRenderer.Remove(ro)
+}
+
+func Add(ro RenderObject) {
+	defaultRenderer.Add(ro)
+}
+
+func SetVirtualResolution(width, height int, integerScaling bool) {
+	defaultRenderer.SetVirtualResolution(width, height, integerScaling)
+}
+
+func ClearVirtualResolution() {
+	defaultRenderer.ClearVirtualResolution()
 }
 
 // Rendering functions
@@ -34,14 +65,35 @@ func PrepRender() {
 }
 
 func Render() {
-	//Process job queue
-	performJobs()
+	defaultRenderer.Render()
+}
+
+// Game loop on the default renderer
+
+func DefaultLoop() *Loop {
+	return defaultLoop
+}
+
+func Run(update func(dt float64), render func(alpha float64)) {
+	defaultLoop.Run(update, render)
+}
 
-	PrepRender()
-	for _, obj := range renderObjects {
-		obj.Render()
-	}
-	window.SwapBuffers()
+func Pause() {
+	defaultLoop.Pause()
+}
+
+func Resume() {
+	defaultLoop.Resume()
+}
+
+func Paused() bool {
+	return defaultLoop.Paused()
+}
+
+func Step() {
+	defaultLoop.Step()
+}
 
-	window.PollInput()
+func Stop() {
+	defaultLoop.Stop()
 }
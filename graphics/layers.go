@@ -0,0 +1,64 @@
+package graphics
+
+import "sort"
+
+/*
+Render objects are drawn by layer then z-index, lowest first. Objects sharing both keep
+their creation order.
+*/
+
+const (
+	LayerBackground = -100
+	LayerWorld      = 0
+	LayerEffects    = 100
+	LayerUI         = 200
+)
+
+func SetLayer(ro RenderObject, layer int) {
+	ro.SetLayer(layer)
+}
+
+func MoveToFront(ro RenderObject) {
+	defaultRenderer.MoveToFront(ro)
+}
+
+func MoveToBack(ro RenderObject) {
+	defaultRenderer.MoveToBack(ro)
+}
+
+// Draw above every other object in the same layer
+func (r *Renderer) MoveToFront(ro RenderObject) {
+	z := ro.ZIndex()
+	for _, other := range r.renderObjects {
+		if other != ro && other.Layer() == ro.Layer() && other.ZIndex() >= z {
+			z = other.ZIndex() + 1
+		}
+	}
+	ro.SetZIndex(z)
+}
+
+// Draw below every other object in the same layer
+func (r *Renderer) MoveToBack(ro RenderObject) {
+	z := ro.ZIndex()
+	for _, other := range r.renderObjects {
+		if other != ro && other.Layer() == ro.Layer() && other.ZIndex() <= z {
+			z = other.ZIndex() - 1
+		}
+	}
+	ro.SetZIndex(z)
+}
+
+func (r *Renderer) sortRenderObjects() {
+	if !r.orderDirty {
+		return
+	}
+
+	sort.SliceStable(r.renderObjects, func(i, j int) bool {
+		a, b := r.renderObjects[i], r.renderObjects[j]
+		if a.Layer() != b.Layer() {
+			return a.Layer() < b.Layer()
+		}
+		return a.ZIndex() < b.ZIndex()
+	})
+	r.orderDirty = false
+}
@@ -0,0 +1,144 @@
+package graphics
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Fixed timestep game loop. Update is called with a constant dt as many times as the elapsed time
+allows, render is then called once with alpha, the fraction of a step left over, to interpolate
+between the last two simulation states. Input is polled before each update step so edge
+triggered input (KeyPressed etc) collected over frames without an update is seen by exactly one
+update, and playback advances a frame per update step.
+
+While paused events are still pumped every frame, so held state and Subscribe handlers stay live
+for render to react to, e.g to unpause. Edges collected while paused are seen by the next update
+step and playback only advances on requested steps.
+*/
+
+const (
+	DefaultTimestep     = time.Second / 60
+	DefaultMaxFrameTime = time.Second / 4
+)
+
+type Loop struct {
+	renderer     *Renderer
+	Timestep     time.Duration // Simulation step passed to update
+	MaxFrameTime time.Duration // Longer frames are clamped so a slow update can't spiral
+	mutex        sync.Mutex
+	paused       bool
+	steps        int
+	stopped      bool
+}
+
+func NewLoop(r *Renderer) *Loop {
+	return &Loop{
+		renderer:     r,
+		Timestep:     DefaultTimestep,
+		MaxFrameTime: DefaultMaxFrameTime,
+	}
+}
+
+// Runs in the calling (opengl) thread until the window is closed or Stop is called, either func can be nil
+func (l *Loop) Run(update func(dt float64), render func(alpha float64)) {
+	l.mutex.Lock()
+	l.stopped = false
+	l.mutex.Unlock()
+
+	window := l.renderer.window
+	previous := time.Now()
+	var accumulator time.Duration
+
+	for !window.ShouldClose() {
+		now := time.Now()
+		frameTime := now.Sub(previous)
+		previous = now
+		if frameTime > l.MaxFrameTime {
+			frameTime = l.MaxFrameTime
+		}
+
+		l.mutex.Lock()
+		if l.stopped {
+			l.mutex.Unlock()
+			return
+		}
+		paused, steps := l.paused, l.steps
+		l.steps = 0
+		l.mutex.Unlock()
+
+		if paused {
+			// Time doesn't accumulate while paused, only requested steps are run. Events are
+			// pumped without advancing playback or the input frame
+			accumulator = 0
+			if steps == 0 {
+				window.PumpEvents()
+			}
+			for ; steps > 0; steps-- {
+				l.step(update)
+			}
+		} else {
+			accumulator += frameTime
+			stepped := false
+			for accumulator >= l.Timestep {
+				l.step(update)
+				accumulator -= l.Timestep
+				stepped = true
+			}
+
+			// Keep the window responsive, edges wait for the next update step
+			if !stepped {
+				window.PumpEvents()
+			}
+		}
+
+		if render != nil {
+			render(float64(accumulator) / float64(l.Timestep))
+		}
+		l.renderer.renderFrame()
+	}
+}
+
+func (l *Loop) step(update func(dt float64)) {
+	l.renderer.window.PollInput()
+	if update != nil {
+		update(l.Timestep.Seconds())
+	}
+}
+
+// Pause and step controls, safe to call from update or another goroutine
+
+func (l *Loop) Pause() {
+	l.mutex.Lock()
+	l.paused = true
+	l.mutex.Unlock()
+}
+
+func (l *Loop) Resume() {
+	l.mutex.Lock()
+	l.paused = false
+	l.steps = 0
+	l.mutex.Unlock()
+}
+
+func (l *Loop) Paused() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.paused
+}
+
+// Run a single update on the next frame while paused
+func (l *Loop) Step() {
+	l.mutex.Lock()
+	if l.paused {
+		l.steps++
+	}
+	l.mutex.Unlock()
+}
+
+// Return from Run after the current frame
+func (l *Loop) Stop() {
+	l.mutex.Lock()
+	l.stopped = true
+	l.mutex.Unlock()
+}
@@ -0,0 +1,202 @@
+package graphics
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Scene graph, nodes carry a local transform and visibility which are propagated to their
+children each frame. Render objects attached to a node are positioned by the node's world
+transform through SetParentTransform, and hidden along with it through SetNodeVisible which
+is combined with the object's own visibility rather than replacing it.
+*/
+
+type NodeObject interface {
+	SetParentTransform(mgl32.Mat3)
+	SetNodeVisible(bool)
+}
+
+type Node struct {
+	mutex    sync.Mutex
+	parent   *Node
+	children []*Node
+	object   NodeObject
+	local    mgl32.Mat3
+	world    mgl32.Mat3
+	visible  bool
+	dirty    bool
+}
+
+func NewNode(object NodeObject) *Node {
+	return &Node{
+		object:  object,
+		local:   mgl32.Ident3(),
+		world:   mgl32.Ident3(),
+		visible: true,
+		dirty:   true,
+	}
+}
+
+// Tree building
+
+// Panics if child is n or one of its ancestors, the cycle would never finish updating
+func (n *Node) AddChild(child *Node) {
+	for ancestor := n; ancestor != nil; ancestor = ancestor.Parent() {
+		if ancestor == child {
+			panic("Attempting to add a node as a child of itself or its descendant")
+		}
+	}
+
+	child.Detach()
+
+	n.mutex.Lock()
+	n.children = append(n.children, child)
+	n.mutex.Unlock()
+
+	child.mutex.Lock()
+	child.parent = n
+	child.dirty = true
+	child.mutex.Unlock()
+}
+
+func (n *Node) RemoveChild(child *Node) {
+	n.mutex.Lock()
+	for i, c := range n.children {
+		if c == child {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			break
+		}
+	}
+	n.mutex.Unlock()
+
+	child.mutex.Lock()
+	removed := child.parent == n
+	if removed {
+		child.parent = nil
+	}
+	child.mutex.Unlock()
+
+	// Detached nodes are no longer updated from the root, recompute the subtree as its own root
+	// so its objects drop the old parent transform and visibility
+	if removed {
+		child.update(mgl32.Ident3(), true, true)
+	}
+}
+
+// Remove the node from its parent, its subtree stays intact
+func (n *Node) Detach() {
+	n.mutex.Lock()
+	parent := n.parent
+	n.mutex.Unlock()
+
+	if parent != nil {
+		parent.RemoveChild(n)
+	}
+}
+
+func (n *Node) Parent() *Node {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.parent
+}
+
+func (n *Node) Children() []*Node {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]*Node{}, n.children...)
+}
+
+func (n *Node) SetObject(object NodeObject) {
+	n.mutex.Lock()
+	n.object = object
+	n.dirty = true
+	n.mutex.Unlock()
+}
+
+func (n *Node) Object() NodeObject {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.object
+}
+
+// Transforms
+
+func (n *Node) SetTransform(transform mgl32.Mat3) {
+	n.mutex.Lock()
+	n.local = transform
+	n.dirty = true
+	n.mutex.Unlock()
+}
+
+func (n *Node) Transform() mgl32.Mat3 {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.local
+}
+
+func (n *Node) applyTransform(m mgl32.Mat3) {
+	n.mutex.Lock()
+	n.local = n.local.Mul3(m)
+	n.dirty = true
+	n.mutex.Unlock()
+}
+
+func (n *Node) Translate(x, y float32) {
+	n.applyTransform(mgl32.Translate2D(x, y))
+}
+
+func (n *Node) Rotate(angle float32) {
+	n.applyTransform(mgl32.HomogRotate2D(angle))
+}
+
+func (n *Node) Scale(x, y float32) {
+	n.applyTransform(mgl32.Scale2D(x, y))
+}
+
+// World transform as of the last update
+func (n *Node) WorldTransform() mgl32.Mat3 {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.world
+}
+
+// Visibility, a hidden node hides all of its children
+
+func (n *Node) SetVisible(visible bool) {
+	n.mutex.Lock()
+	n.visible = visible
+	n.dirty = true
+	n.mutex.Unlock()
+}
+
+func (n *Node) Visible() bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.visible
+}
+
+// Called in the opengl thread before rendering
+func (n *Node) update(parentWorld mgl32.Mat3, parentVisible, parentChanged bool) {
+	n.mutex.Lock()
+	changed := n.dirty || parentChanged
+	if changed {
+		n.world = parentWorld.Mul3(n.local)
+	}
+	world := n.world
+	visible := parentVisible && n.visible
+	object := n.object
+	children := append([]*Node{}, n.children...)
+	n.dirty = false
+	n.mutex.Unlock()
+
+	if changed && object != nil {
+		object.SetParentTransform(world)
+		object.SetNodeVisible(visible)
+	}
+
+	for _, child := range children {
+		child.update(world, visible, changed)
+	}
+}
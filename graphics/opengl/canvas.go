@@ -0,0 +1,72 @@
+package opengl
+
+import "math"
+
+/*
+The canvas is the pixel space render objects are projected from. By default it is the window,
+with a virtual resolution it is a fixed size that is scaled to fit the window with letterbox or
+pillarbox bars, optionally by whole multiples only to keep pixel art crisp.
+*/
+
+func (w *Window) SetVirtualResolution(width, height int, integerScaling bool) {
+	w.virtualWidth, w.virtualHeight = width, height
+	w.integerScaling = integerScaling
+}
+
+func (w *Window) ClearVirtualResolution() {
+	w.virtualWidth, w.virtualHeight = 0, 0
+}
+
+func (w *Window) VirtualResolution() (int, int, bool) {
+	return w.virtualWidth, w.virtualHeight, w.virtualWidth > 0 && w.virtualHeight > 0
+}
+
+// Size of the pixel space render objects are positioned in
+func (w *Window) CanvasSize() (float64, float64) {
+	if vw, vh, ok := w.VirtualResolution(); ok {
+		return float64(vw), float64(vh)
+	}
+
+	return w.Width, w.Height
+}
+
+// Area of the framebuffer the canvas is drawn to, in framebuffer pixels from the bottom left
+func (w *Window) CanvasRect() (x, y, width, height int) {
+	vw, vh, ok := w.VirtualResolution()
+	if !ok {
+		return 0, 0, w.FramebufferWidth, w.FramebufferHeight
+	}
+
+	scale := math.Min(float64(w.FramebufferWidth)/float64(vw), float64(w.FramebufferHeight)/float64(vh))
+	// Windows smaller than the canvas still get a fractional scale
+	if w.integerScaling && scale >= 1 {
+		scale = math.Floor(scale)
+	}
+
+	width = int(float64(vw) * scale)
+	height = int(float64(vh) * scale)
+	x = (w.FramebufferWidth - width) / 2
+	y = (w.FramebufferHeight - height) / 2
+
+	return x, y, width, height
+}
+
+// Map a position in window coordinates, 0,0 top left as glfw reports them, to canvas
+// pixels with 0,0 at the bottom left. Positions in the bars fall outside the canvas.
+func (w *Window) WindowToCanvas(x, y float64) (float32, float32) {
+	scaleX, scaleY := w.ContentScale()
+
+	// Into framebuffer pixels from the bottom left
+	fx := x * float64(scaleX)
+	fy := float64(w.FramebufferHeight) - y*float64(scaleY)
+
+	rx, ry, rw, rh := w.CanvasRect()
+	cw, ch := w.CanvasSize()
+
+	return float32((fx - float64(rx)) * cw / float64(rw)), float32((fy - float64(ry)) * ch / float64(rh))
+}
+
+// Current cursor position in canvas pixels
+func (w *Window) CanvasCursorPos() (float32, float32) {
+	return w.WindowToCanvas(w.GlWindow.GetCursorPos())
+}
@@ -3,17 +3,29 @@ package opengl
 import (
 	"sync"
 
+	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+/*
+Vertices are transformed by projection * view * model, all affine 2D transforms in
+homogeneous coordinates. The model is composed as parent * convenience * transform where
+the convenience matrix is built from the translation/rotation/scale pointers.
+*/
+
 type DefaultVAO struct {
 	*BaseVAO
-	updateMutex                     sync.Mutex
-	cam, position                   *mgl32.Vec2
-	bounds                          mgl32.Vec4
-	checkBounds                     bool
-	position_pointers, cam_pointers []*float32
-	pointers_updated                bool
+	updateMutex                        sync.Mutex
+	model, view, projection            mgl32.Mat3
+	transform, parent, viewTransform   mgl32.Mat3
+	bounds                             mgl32.Vec4
+	checkBounds                        bool
+	position_pointers, cam_pointers    []*float32
+	rotcenter_pointers, scale_pointers []*float32
+	rotation_pointer, zoom_pointer     *float32
+	pointers_updated                   bool
+	projectionWidth, projectionHeight  float64
+	vertices                           int
 }
 
 func CreateDefaultVao(window *Window, textureSource string, elements int) *DefaultVAO {
@@ -32,11 +44,28 @@ func CreateDefaultVao(window *Window, textureSource string, elements int) *Defau
 	vBuff.Elements = vElements
 	tBuff.Elements = tElements
 
+	// Colour and rotation buffers are only created once used, see ColourBuffer
 	vao.AddBuffer("vert", &vBuff)
 	vao.AddBuffer("verttexcoord", &tBuff)
 
-	var x, y, cx, cy float32
-	defaultVAO := DefaultVAO{vao, sync.Mutex{}, &mgl32.Vec2{}, &mgl32.Vec2{}, mgl32.Vec4{}, false, []*float32{&x, &y}, []*float32{&cx, &cy}, false}
+	var x, y, cx, cy, rx, ry, angle float32
+	var sx, sy, zoom float32 = 1, 1, 1
+	defaultVAO := DefaultVAO{
+		BaseVAO:            vao,
+		model:              mgl32.Ident3(),
+		view:               mgl32.Ident3(),
+		transform:          mgl32.Ident3(),
+		parent:             mgl32.Ident3(),
+		viewTransform:      mgl32.Ident3(),
+		position_pointers:  []*float32{&x, &y},
+		cam_pointers:       []*float32{&cx, &cy},
+		rotcenter_pointers: []*float32{&rx, &ry},
+		scale_pointers:     []*float32{&sx, &sy},
+		rotation_pointer:   &angle,
+		zoom_pointer:       &zoom,
+		vertices:           elements * 3,
+	}
+	defaultVAO.projection = defaultVAO.pixelProjection()
 
 	defaultVAO.AttachDefaultShader()
 	defaultVAO.Init()
@@ -44,17 +73,74 @@ func CreateDefaultVao(window *Window, textureSource string, elements int) *Defau
 	return &defaultVAO
 }
 
+/*
+Most objects are never tinted or rotated, so the per vertex colour and rotation buffers are
+created on first use. Until then the shader reads constant attributes, white and no rotation.
+Created buffers are uploaded by the next UpdateBuffers.
+*/
+
+// rgba per vertex, defaulting to white so untinted vertices render the texture as is
+func (vao *DefaultVAO) ColourBuffer() *Buffer {
+	if b := vao.GetBuffer("vertcolour"); b != nil {
+		return b
+	}
+
+	elements := make([]float32, vao.vertices*4)
+	for i := range elements {
+		elements[i] = 1
+	}
+
+	b := &Buffer{
+		Dimension: 4,
+		Elements:  elements,
+	}
+	vao.AddBuffer("vertcolour", b)
+
+	return b
+}
+
+// Rotation center, cos and sin per vertex, defaulting to no rotation
+func (vao *DefaultVAO) RotationBuffer() *Buffer {
+	if b := vao.GetBuffer("rotgroup"); b != nil {
+		return b
+	}
+
+	elements := make([]float32, vao.vertices*4)
+	for i := 2; i < len(elements); i += 4 {
+		elements[i] = 1
+	}
+
+	b := &Buffer{
+		Dimension: 4,
+		Elements:  elements,
+	}
+	vao.AddBuffer("rotgroup", b)
+
+	return b
+}
+
+// Constant values for attributes without a buffer. Generic attribute values are context state
+// and undefined after drawing with the array enabled, so they are set before every draw
+func (vao *DefaultVAO) setConstantAttributes() {
+	if vao.GetBuffer("vertcolour") == nil {
+		gl.VertexAttrib4f(vao.shader.attributes["vertcolour"], 1, 1, 1, 1)
+	}
+	if vao.GetBuffer("rotgroup") == nil {
+		gl.VertexAttrib4f(vao.shader.attributes["rotgroup"], 0, 0, 1, 0)
+	}
+}
+
 /*
 Pointers are set beforehand and updated, theadsafe? No. Works? Yes.
 For actual stuff that requires thread safe operators (eg not just setting rotations) we can use the jobBlocks.
 */
 
-// Called from non opengl thread
+// Called from non opengl thread, values are read on the next render after UpdatePointers
 func (vao *DefaultVAO) SetTranslation(x, y *float32) {
 	vao.updateMutex.Lock()
 	vao.position_pointers[0] = x
 	vao.position_pointers[1] = y
-	vao.UpdateUniforms()
+	vao.pointers_updated = true
 	vao.updateMutex.Unlock()
 }
 
@@ -62,33 +148,189 @@ func (vao *DefaultVAO) SetCam(x, y *float32) {
 	vao.updateMutex.Lock()
 	vao.cam_pointers[0] = x
 	vao.cam_pointers[1] = y
-	vao.UpdateUniforms()
+	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
+}
+
+// Angle in radians, counter clockwise around the rotation center
+func (vao *DefaultVAO) SetRotation(angle *float32) {
+	vao.updateMutex.Lock()
+	vao.rotation_pointer = angle
+	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
+}
+
+func (vao *DefaultVAO) SetRotationCenter(x, y *float32) {
+	vao.updateMutex.Lock()
+	vao.rotcenter_pointers[0] = x
+	vao.rotcenter_pointers[1] = y
+	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
+}
+
+// Scale is applied around the rotation center
+func (vao *DefaultVAO) SetScale(x, y *float32) {
+	vao.updateMutex.Lock()
+	vao.scale_pointers[0] = x
+	vao.scale_pointers[1] = y
+	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
+}
+
+func (vao *DefaultVAO) SetZoom(zoom *float32) {
+	vao.updateMutex.Lock()
+	vao.zoom_pointer = zoom
+	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
+}
+
+// Matrix transforms, these are applied to the vertices before the convenience setters above
+
+func (vao *DefaultVAO) SetTransform(transform mgl32.Mat3) {
+	vao.updateMutex.Lock()
+	vao.transform = transform
+	vao.pointers_updated = true
 	vao.updateMutex.Unlock()
 }
 
+func (vao *DefaultVAO) Transform() mgl32.Mat3 {
+	vao.updateMutex.Lock()
+	defer vao.updateMutex.Unlock()
+	return vao.transform
+}
+
+// Compose m onto the current transform, m is applied to the vertices first
+func (vao *DefaultVAO) ApplyTransform(m mgl32.Mat3) {
+	vao.updateMutex.Lock()
+	vao.transform = vao.transform.Mul3(m)
+	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
+}
+
+func (vao *DefaultVAO) Translate(x, y float32) {
+	vao.ApplyTransform(mgl32.Translate2D(x, y))
+}
+
+func (vao *DefaultVAO) Rotate(angle float32) {
+	vao.ApplyTransform(mgl32.HomogRotate2D(angle))
+}
+
+func (vao *DefaultVAO) Scale(x, y float32) {
+	vao.ApplyTransform(mgl32.Scale2D(x, y))
+}
+
+// Skew by factors, x += kx*y and y += ky*x
+func (vao *DefaultVAO) Skew(kx, ky float32) {
+	vao.ApplyTransform(Skew2D(kx, ky))
+}
+
+// World transform of the parent, composed before everything else in the model
+func (vao *DefaultVAO) SetParentTransform(parent mgl32.Mat3) {
+	vao.updateMutex.Lock()
+	vao.parent = parent
+	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
+}
+
+// Additional view transform applied after the cam and zoom
+func (vao *DefaultVAO) SetView(view mgl32.Mat3) {
+	vao.updateMutex.Lock()
+	vao.viewTransform = view
+	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
+}
+
+// Matrices last uploaded to the shader
+
+func (vao *DefaultVAO) Model() mgl32.Mat3 {
+	return vao.model
+}
+
+func (vao *DefaultVAO) View() mgl32.Mat3 {
+	return vao.view
+}
+
+func (vao *DefaultVAO) Projection() mgl32.Mat3 {
+	return vao.projection
+}
+
+// Canvas pixels to the space objects are placed in, i.e undoing the cam, zoom and view
+func (vao *DefaultVAO) CanvasToWorld(x, y float32) (float32, float32) {
+	p := vao.view.Inv().Mul3x1(mgl32.Vec3{x, y, 1})
+	return p.X(), p.Y()
+}
+
+// Canvas pixels to the object's vertex space, additionally undoing the model transform
+func (vao *DefaultVAO) CanvasToLocal(x, y float32) (float32, float32) {
+	p := vao.view.Mul3(vao.model).Inv().Mul3x1(mgl32.Vec3{x, y, 1})
+	return p.X(), p.Y()
+}
+
 func (vao *DefaultVAO) UpdatePointers() {
+	vao.updateMutex.Lock()
 	vao.pointers_updated = true
+	vao.updateMutex.Unlock()
 }
 
 // Called in the opengl thread
 func (vao *DefaultVAO) updatePointers() {
 	vao.updateMutex.Lock()
-	vao.position[0] = *vao.position_pointers[0]
-	vao.position[1] = *vao.position_pointers[1]
-	vao.cam[0] = *vao.cam_pointers[0]
-	vao.cam[1] = *vao.cam_pointers[1]
+	translation := mgl32.Translate2D(*vao.position_pointers[0], *vao.position_pointers[1])
+	center := mgl32.Vec2{*vao.rotcenter_pointers[0], *vao.rotcenter_pointers[1]}
+	rotation := mgl32.HomogRotate2D(*vao.rotation_pointer)
+	scale := mgl32.Scale2D(*vao.scale_pointers[0], *vao.scale_pointers[1])
+
+	// Scale and rotate around the center then translate
+	convenience := translation.
+		Mul3(mgl32.Translate2D(center.X(), center.Y())).
+		Mul3(rotation).
+		Mul3(scale).
+		Mul3(mgl32.Translate2D(-center.X(), -center.Y()))
+	vao.model = vao.parent.Mul3(convenience).Mul3(vao.transform)
+
+	zoom := *vao.zoom_pointer
+	cam := mgl32.Translate2D(-*vao.cam_pointers[0], -*vao.cam_pointers[1])
+	vao.view = vao.viewTransform.Mul3(mgl32.Scale2D(zoom, zoom)).Mul3(cam)
+
+	// Cleared under the lock so a Set* landing after the read isn't lost
+	vao.pointers_updated = false
 	vao.updateMutex.Unlock()
+
+	vao.projection = vao.pixelProjection()
 	vao.UpdateUniforms()
-	vao.pointers_updated = false
+}
+
+// Maps pixel coordinates with 0,0 at the bottom left to normalized device coordinates
+func (vao *DefaultVAO) pixelProjection() mgl32.Mat3 {
+	width, height := vao.window.CanvasSize()
+	vao.projectionWidth, vao.projectionHeight = width, height
+
+	return mgl32.Mat3{
+		2. / float32(width), 0, 0,
+		0, 2. / float32(height), 0,
+		-1, -1, 1,
+	}
+}
+
+// The default shader program is created per VAO so is deleted with it
+func (vao *DefaultVAO) Delete() {
+	vao.BaseVAO.Delete()
+	vao.shader.Delete()
 }
 
 // Rendering logic
 func (vao *DefaultVAO) PrepRender() {
 	// Prep for render, bind the VAO and shader
 	vao.BaseVAO.PrepRender()
+	vao.setConstantAttributes()
 
-	// Prep the pointers if updated
-	if vao.pointers_updated {
+	// Prep the pointers if updated, or the projection if the canvas was resized
+	width, height := vao.window.CanvasSize()
+	resized := vao.projectionWidth != width || vao.projectionHeight != height
+	vao.updateMutex.Lock()
+	updated := vao.pointers_updated
+	vao.updateMutex.Unlock()
+	if updated || resized {
 		vao.updatePointers()
 	}
 }
@@ -102,20 +344,13 @@ func (vao *DefaultVAO) AttachDefaultShader() {
 	program.Link()
 
 	program.AddAttribute("vert")
-	// Currently unusued, optimized out by the shader compiler so will fail
-	// program.AddAttribute("rotgroup")
+	program.AddAttribute("rotgroup")
 	program.AddAttribute("verttexcoord")
+	program.AddAttribute("vertcolour")
 
-	// Add and set rotation uniform
-	vao.AddUniform("rot", &mgl32.Mat2{1, 0, 0, 1})
-	vao.AddUniform("rotcenter", &mgl32.Vec2{0, 0})
-
-	// Other uniforms can use default values.
-	var zoom float32 = 1
-	vao.AddUniform("trans", vao.position)
-	vao.AddUniform("dim", &mgl32.Mat2{2. / float32(vao.window.Width), 0., 0., 2. / float32(vao.window.Height)})
-	vao.AddUniform("cam", vao.cam)
-	vao.AddUniform("zoom", &zoom)
+	vao.AddUniform("model", &vao.model)
+	vao.AddUniform("view", &vao.view)
+	vao.AddUniform("projection", &vao.projection)
 }
 
 // CPU side culling
@@ -130,28 +365,32 @@ func (vao *DefaultVAO) ShouldRender() bool {
 		return true
 	}
 
-	// Check if we are within bounds
-	windowWidth := vao.window.Width
-	windowHeight := vao.window.Height
-
-	bPos := mgl32.Vec2{vao.bounds.X(), vao.bounds.Y()}.Add(*vao.position).Sub(*vao.cam)
-	bPosBoundary := mgl32.Vec2{vao.bounds.Z(), vao.bounds.W()}.Add(bPos)
-
-	if bPos.X() > float32(windowWidth) && bPosBoundary.X() > float32(windowWidth) {
-		return false
+	// Transform the bounds corners into normalized device coordinates
+	mvp := vao.projection.Mul3(vao.view).Mul3(vao.model)
+	x, y, w, h := vao.bounds.X(), vao.bounds.Y(), vao.bounds.Z(), vao.bounds.W()
+	corners := [4]mgl32.Vec3{
+		mvp.Mul3x1(mgl32.Vec3{x, y, 1}),
+		mvp.Mul3x1(mgl32.Vec3{x + w, y, 1}),
+		mvp.Mul3x1(mgl32.Vec3{x, y + h, 1}),
+		mvp.Mul3x1(mgl32.Vec3{x + w, y + h, 1}),
 	}
 
-	if bPos.X() < 0 && bPosBoundary.X() < 0 {
-		return false
+	// Cull only if every corner is off the same side of the screen
+	left, right, bottom, top := true, true, true, true
+	for _, c := range corners {
+		left = left && c.X() < -1
+		right = right && c.X() > 1
+		bottom = bottom && c.Y() < -1
+		top = top && c.Y() > 1
 	}
 
-	if bPos.Y() > float32(windowHeight) && bPosBoundary.Y() > float32(windowHeight) {
-		return false
-	}
+	return !(left || right || bottom || top)
+}
 
-	if bPos.Y() < 0 && bPosBoundary.Y() < 0 {
-		return false
+func Skew2D(kx, ky float32) mgl32.Mat3 {
+	return mgl32.Mat3{
+		1, ky, 0,
+		kx, 1, 0,
+		0, 0, 1,
 	}
-
-	return true
 }
@@ -0,0 +1,222 @@
+package opengl
+
+import (
+	"sync"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+/*
+Every glfw callback is turned into an Event which updates the window's input state and is then
+passed to subscribed handlers and channels. Handlers run in the opengl thread during PollInput,
+channels can be consumed from any goroutine.
+*/
+
+type EventType int
+
+const (
+	EventKey EventType = iota
+	EventChar
+	EventMouseButton
+	EventMouseMove
+	EventScroll
+	EventFocus
+	EventResize
+	EventClose
+	EventGamepadConnected
+	EventGamepadDisconnected
+	EventGamepadButton
+	EventGamepadAxis
+	EventFramebufferResize
+)
+
+type Action int
+
+const (
+	ActionRelease Action = Action(glfw.Release)
+	ActionPress   Action = Action(glfw.Press)
+	ActionRepeat  Action = Action(glfw.Repeat)
+)
+
+// Only the fields relevant to the event type are set
+type Event struct {
+	Type          EventType
+	Key           Key
+	Scancode      int
+	Action        Action
+	Mods          ModifierKey
+	Char          rune
+	Button        MouseButton
+	X, Y          float64 // Cursor position in window coordinates, or scroll offsets
+	Focused       bool
+	Width, Height int // Window or framebuffer size
+	Joystick      Joystick
+	GamepadButton GamepadButton
+	GamepadAxis   GamepadAxis
+	Value         float32 // Gamepad axis value
+	Name          string  // Name of a connected gamepad
+}
+
+type eventSubscribers struct {
+	mutex    sync.Mutex
+	nextId   int
+	handlers map[int]func(Event)
+	channels map[int]chan Event
+}
+
+// Handler is called in the opengl thread for every event, call the returned func to unsubscribe
+func (w *Window) Subscribe(handler func(Event)) func() {
+	s := &w.subscribers
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[int]func(Event))
+	}
+	id := s.nextId
+	s.nextId++
+	s.handlers[id] = handler
+
+	return func() {
+		s.mutex.Lock()
+		delete(s.handlers, id)
+		s.mutex.Unlock()
+	}
+}
+
+/*
+Buffered channel of events, safe to read from another goroutine. Events are dropped rather than
+blocking the render loop if the buffer is full. Unsubscribing closes the channel.
+*/
+
+func (w *Window) EventChannel(buffer int) (<-chan Event, func()) {
+	s := &w.subscribers
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.channels == nil {
+		s.channels = make(map[int]chan Event)
+	}
+	id := s.nextId
+	s.nextId++
+	ch := make(chan Event, buffer)
+	s.channels[id] = ch
+
+	return ch, func() {
+		s.mutex.Lock()
+		if _, ok := s.channels[id]; ok {
+			delete(s.channels, id)
+			close(ch)
+		}
+		s.mutex.Unlock()
+	}
+}
+
+func (w *Window) dispatch(ev Event) {
+	// Recorded with the frame PollInput swaps them into
+	w.keyMutex.Lock()
+	if w.recorder != nil {
+		w.pending.events = append(w.pending.events, ev)
+	}
+	w.keyMutex.Unlock()
+
+	w.apply(ev)
+
+	s := &w.subscribers
+	s.mutex.Lock()
+	handlers := make([]func(Event), 0, len(s.handlers))
+	for _, handler := range s.handlers {
+		handlers = append(handlers, handler)
+	}
+	for _, ch := range s.channels {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+	s.mutex.Unlock()
+
+	// Outside the lock so handlers can unsubscribe
+	for _, handler := range handlers {
+		handler(ev)
+	}
+}
+
+// Live input from glfw, dropped while playing back a recording so only recorded input is seen
+func (w *Window) dispatchInput(ev Event) {
+	if w.Playing() {
+		return
+	}
+
+	w.dispatch(ev)
+}
+
+// glfw callbacks
+
+func (w *Window) setEventCallbacks() {
+	window := w.GlWindow
+	window.SetKeyCallback(w.keyCallback)
+	window.SetCharModsCallback(w.charModsCallback)
+	window.SetMouseButtonCallback(w.mouseButtonCallback)
+	window.SetCursorPosCallback(w.cursorPosCallback)
+	window.SetScrollCallback(w.scrollCallback)
+	window.SetFocusCallback(w.focusCallback)
+	window.SetCloseCallback(w.closeCallback)
+}
+
+func (w *Window) keyCallback(_ *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	w.dispatchInput(Event{
+		Type:     EventKey,
+		Key:      Key(key),
+		Scancode: scancode,
+		Action:   Action(action),
+		Mods:     ModifierKey(mods),
+	})
+}
+
+// Unicode text input, reported with the modifiers held so shortcuts can be filtered out
+func (w *Window) charModsCallback(_ *glfw.Window, char rune, mods glfw.ModifierKey) {
+	w.dispatchInput(Event{
+		Type: EventChar,
+		Char: char,
+		Mods: ModifierKey(mods),
+	})
+}
+
+func (w *Window) mouseButtonCallback(_ *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	w.dispatchInput(Event{
+		Type:   EventMouseButton,
+		Button: MouseButton(button),
+		Action: Action(action),
+		Mods:   ModifierKey(mods),
+	})
+}
+
+func (w *Window) cursorPosCallback(_ *glfw.Window, x, y float64) {
+	w.dispatchInput(Event{
+		Type: EventMouseMove,
+		X:    x,
+		Y:    y,
+	})
+}
+
+func (w *Window) scrollCallback(_ *glfw.Window, x, y float64) {
+	w.dispatchInput(Event{
+		Type: EventScroll,
+		X:    x,
+		Y:    y,
+	})
+}
+
+func (w *Window) focusCallback(_ *glfw.Window, focused bool) {
+	w.dispatch(Event{
+		Type:    EventFocus,
+		Focused: focused,
+	})
+}
+
+func (w *Window) closeCallback(_ *glfw.Window) {
+	w.dispatch(Event{
+		Type: EventClose,
+	})
+}
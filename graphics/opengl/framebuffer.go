@@ -0,0 +1,106 @@
+package opengl
+
+import "github.com/go-gl/gl/v4.1-core/gl"
+
+/*
+Offscreen render target with a single colour texture, used to render at a fixed resolution
+and scale the result to the window.
+*/
+
+type Framebuffer struct {
+	id            uint32
+	texture       uint32
+	Width, Height int
+}
+
+func CreateFramebuffer(width, height int) *Framebuffer {
+	fb := &Framebuffer{
+		Width:  width,
+		Height: height,
+	}
+
+	gl.GenTextures(1, &fb.texture)
+	bindTexture(gl.TEXTURE0, fb.texture)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	bindTexture(gl.TEXTURE0, 0)
+
+	gl.GenFramebuffers(1, &fb.id)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.id)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, fb.texture, 0)
+
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		panic("Framebuffer incomplete")
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	return fb
+}
+
+// Render into the framebuffer, the viewport is set to its size
+func (fb *Framebuffer) Bind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.id)
+	gl.Viewport(0, 0, int32(fb.Width), int32(fb.Height))
+}
+
+func (fb *Framebuffer) Unbind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+/*
+Draw the framebuffer's texture to the given rect of the default framebuffer as a quad. Unlike
+BlitTo this works when the window is multisampled and the sizes differ.
+*/
+
+func (fb *Framebuffer) Present(x, y, width, height int) {
+	w := currentWindow
+	if w.presentProgram == nil {
+		program := CreateProgram(0)
+		program.LoadVertShader("./resources/shaders/present.vert")
+		program.LoadFragShader("./resources/shaders/present.frag")
+		program.Link()
+
+		w.presentProgram = program
+		w.presentVao = w.GetVAOId()
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(int32(x), int32(y), int32(width), int32(height))
+	w.viewportDirty = true
+
+	// The canvas is already blended, copy it as is
+	SetBlend(false)
+	w.presentProgram.Use()
+	bindVertexArray(w.presentVao)
+	bindTexture(gl.TEXTURE0, fb.texture)
+
+	stats := currentStats()
+	stats.DrawCalls++
+	stats.Vertices += 4
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+
+	SetBlend(true)
+}
+
+// Copy to the given rect of the default framebuffer without filtering, the window must not be
+// multisampled unless the sizes match, use Present otherwise
+func (fb *Framebuffer) BlitTo(x, y, width, height int) {
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, fb.id)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, 0)
+	gl.BlitFramebuffer(
+		0, 0, int32(fb.Width), int32(fb.Height),
+		int32(x), int32(y), int32(x+width), int32(y+height),
+		gl.COLOR_BUFFER_BIT, gl.NEAREST,
+	)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+func (fb *Framebuffer) Delete() {
+	gl.DeleteFramebuffers(1, &fb.id)
+	forgetTexture(fb.texture)
+	gl.DeleteTextures(1, &fb.texture)
+}
@@ -0,0 +1,274 @@
+package opengl
+
+import (
+	"math"
+	"sync"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+/*
+Gamepads are read through the glfw 3.2 joystick API which only reports raw buttons and axes, so
+they are mapped onto a standard layout here. The default mapping is the XInput layout glfw
+reports on Windows, other controllers can be mapped by name with SetGamepadMapping.
+
+Joysticks are polled in PollInput and every change, including connection and disconnection, is
+dispatched as an event like keyboard input.
+*/
+
+type Joystick int
+
+const MaxJoysticks = 16
+
+type GamepadButton int
+
+const (
+	ButtonA GamepadButton = iota
+	ButtonB
+	ButtonX
+	ButtonY
+	ButtonLeftBumper
+	ButtonRightBumper
+	ButtonBack
+	ButtonStart
+	ButtonGuide
+	ButtonLeftThumb
+	ButtonRightThumb
+	ButtonDpadUp
+	ButtonDpadRight
+	ButtonDpadDown
+	ButtonDpadLeft
+	GamepadButtonCount
+)
+
+// Sticks are -1 to 1 with y positive downwards, triggers are 0 released to 1 fully pressed
+type GamepadAxis int
+
+const (
+	AxisLeftX GamepadAxis = iota
+	AxisLeftY
+	AxisRightX
+	AxisRightY
+	AxisLeftTrigger
+	AxisRightTrigger
+	GamepadAxisCount
+)
+
+// Raw indexes for each standard button and axis, -1 if the controller does not have it
+type GamepadMapping struct {
+	Buttons [GamepadButtonCount]int
+	Axes    [GamepadAxisCount]int
+	Invert  [GamepadAxisCount]bool
+}
+
+var (
+	XInputMapping = GamepadMapping{
+		Buttons: [GamepadButtonCount]int{0, 1, 2, 3, 4, 5, 6, 7, -1, 8, 9, 10, 11, 12, 13},
+		Axes:    [GamepadAxisCount]int{0, 1, 2, 3, 4, 5},
+		Invert:  [GamepadAxisCount]bool{false, true, false, true, false, false},
+	}
+	gamepadMappings     = make(map[string]GamepadMapping)
+	gamepadMappingMutex sync.Mutex
+)
+
+const DefaultDeadzone = 0.15
+
+// Use mapping for every joystick reporting this name
+func SetGamepadMapping(name string, mapping GamepadMapping) {
+	gamepadMappingMutex.Lock()
+	gamepadMappings[name] = mapping
+	gamepadMappingMutex.Unlock()
+}
+
+func gamepadMapping(name string) GamepadMapping {
+	gamepadMappingMutex.Lock()
+	defer gamepadMappingMutex.Unlock()
+
+	if mapping, ok := gamepadMappings[name]; ok {
+		return mapping
+	}
+
+	return XInputMapping
+}
+
+type gamepadState struct {
+	name    string
+	buttons [GamepadButtonCount]bool
+	axes    [GamepadAxisCount]float32
+}
+
+type gamepadButtonKey struct {
+	joystick Joystick
+	button   GamepadButton
+}
+
+// Polling, called from PollInput
+
+func (w *Window) pollGamepads() {
+	for j := Joystick(0); j < MaxJoysticks; j++ {
+		w.keyMutex.Lock()
+		state, connected := w.gamepads[j]
+		deadzone := w.deadzone
+		w.keyMutex.Unlock()
+
+		present := glfw.JoystickPresent(glfw.Joystick(j))
+		if !present {
+			if connected {
+				w.dispatch(Event{Type: EventGamepadDisconnected, Joystick: j})
+			}
+			continue
+		}
+
+		name := glfw.GetJoystickName(glfw.Joystick(j))
+		if !connected {
+			w.dispatch(Event{Type: EventGamepadConnected, Joystick: j, Name: name})
+			state = &gamepadState{name: name}
+		}
+
+		buttons, axes := readGamepad(j, gamepadMapping(name), deadzone)
+
+		for b, down := range buttons {
+			if down != state.buttons[b] {
+				action := ActionRelease
+				if down {
+					action = ActionPress
+				}
+				w.dispatch(Event{Type: EventGamepadButton, Joystick: j, GamepadButton: GamepadButton(b), Action: action})
+			}
+		}
+
+		for a, value := range axes {
+			if value != state.axes[a] {
+				w.dispatch(Event{Type: EventGamepadAxis, Joystick: j, GamepadAxis: GamepadAxis(a), Value: value})
+			}
+		}
+	}
+}
+
+func readGamepad(j Joystick, mapping GamepadMapping, deadzone float32) ([GamepadButtonCount]bool, [GamepadAxisCount]float32) {
+	rawButtons := glfw.GetJoystickButtons(glfw.Joystick(j))
+	rawAxes := glfw.GetJoystickAxes(glfw.Joystick(j))
+
+	var buttons [GamepadButtonCount]bool
+	for b, index := range mapping.Buttons {
+		if index >= 0 && index < len(rawButtons) {
+			buttons[b] = glfw.Action(rawButtons[index]) == glfw.Press
+		}
+	}
+
+	var axes [GamepadAxisCount]float32
+	var read [GamepadAxisCount]bool
+	for a, index := range mapping.Axes {
+		if index >= 0 && index < len(rawAxes) {
+			axes[a] = rawAxes[index]
+			if mapping.Invert[a] {
+				axes[a] = -axes[a]
+			}
+			read[a] = true
+		}
+	}
+
+	// Raw triggers rest at -1, missing triggers stay released at 0
+	for _, a := range []GamepadAxis{AxisLeftTrigger, AxisRightTrigger} {
+		if read[a] {
+			axes[a] = applyDeadzone((axes[a]+1)/2, deadzone)
+		}
+	}
+
+	axes[AxisLeftX], axes[AxisLeftY] = applyStickDeadzone(axes[AxisLeftX], axes[AxisLeftY], deadzone)
+	axes[AxisRightX], axes[AxisRightY] = applyStickDeadzone(axes[AxisRightX], axes[AxisRightY], deadzone)
+
+	return buttons, axes
+}
+
+// Values inside the deadzone are zeroed and the remainder rescaled so output still reaches 1
+func applyDeadzone(value, deadzone float32) float32 {
+	if value < deadzone {
+		return 0
+	}
+
+	return (value - deadzone) / (1 - deadzone)
+}
+
+// Radial deadzone so diagonals behave the same as the axes
+func applyStickDeadzone(x, y, deadzone float32) (float32, float32) {
+	magnitude := float32(math.Hypot(float64(x), float64(y)))
+	if magnitude < deadzone {
+		return 0, 0
+	}
+
+	scaled := applyDeadzone(magnitude, deadzone)
+	if scaled > 1 {
+		scaled = 1
+	}
+
+	return x / magnitude * scaled, y / magnitude * scaled
+}
+
+// Queries
+
+func (w *Window) SetGamepadDeadzone(deadzone float32) {
+	w.keyMutex.Lock()
+	w.deadzone = deadzone
+	w.keyMutex.Unlock()
+}
+
+// Connected joysticks in index order
+func (w *Window) Gamepads() []Joystick {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	var joysticks []Joystick
+	for j := Joystick(0); j < MaxJoysticks; j++ {
+		if _, ok := w.gamepads[j]; ok {
+			joysticks = append(joysticks, j)
+		}
+	}
+
+	return joysticks
+}
+
+func (w *Window) GamepadName(j Joystick) string {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	if state, ok := w.gamepads[j]; ok {
+		return state.name
+	}
+
+	return ""
+}
+
+func (w *Window) GamepadButtonHeld(j Joystick, button GamepadButton) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	if state, ok := w.gamepads[j]; ok {
+		return state.buttons[button]
+	}
+
+	return false
+}
+
+func (w *Window) GamepadButtonPressed(j Joystick, button GamepadButton) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.gamepadPressed[gamepadButtonKey{j, button}]
+}
+
+func (w *Window) GamepadButtonReleased(j Joystick, button GamepadButton) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.gamepadReleased[gamepadButtonKey{j, button}]
+}
+
+func (w *Window) GamepadAxis(j Joystick, axis GamepadAxis) float32 {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	if state, ok := w.gamepads[j]; ok {
+		return state.axes[axis]
+	}
+
+	return 0
+}
@@ -0,0 +1,98 @@
+package opengl
+
+import (
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+GPU time elapsed between Begin and End using timer queries. Results arrive a few frames late,
+queries are kept in a ring so reading them never stalls the pipeline.
+*/
+
+const gpuTimerQueries = 4
+
+type GPUTimer struct {
+	queries   [gpuTimerQueries]uint32
+	pending   [gpuTimerQueries]bool
+	next      int
+	last      time.Duration
+	available bool
+	running   bool
+}
+
+// Requires the context to be current, timing is disabled if the driver has no timer bits
+func CreateGPUTimer() *GPUTimer {
+	var bits int32
+	gl.GetQueryiv(gl.TIME_ELAPSED, gl.QUERY_COUNTER_BITS, &bits)
+
+	t := &GPUTimer{
+		available: bits > 0,
+	}
+	if t.available {
+		gl.GenQueries(gpuTimerQueries, &t.queries[0])
+	}
+
+	return t
+}
+
+func (t *GPUTimer) Available() bool {
+	return t.available
+}
+
+func (t *GPUTimer) Begin() {
+	// Skip the frame rather than wait if the ring is full
+	if !t.available || t.pending[t.next] {
+		return
+	}
+
+	gl.BeginQuery(gl.TIME_ELAPSED, t.queries[t.next])
+	t.running = true
+}
+
+func (t *GPUTimer) End() {
+	if !t.running {
+		return
+	}
+
+	gl.EndQuery(gl.TIME_ELAPSED)
+	t.pending[t.next] = true
+	t.next = (t.next + 1) % gpuTimerQueries
+	t.running = false
+}
+
+// Most recent finished measurement, false until one is available
+func (t *GPUTimer) Result() (time.Duration, bool) {
+	if !t.available {
+		return 0, false
+	}
+
+	// Oldest query first, results complete in order
+	for i := 0; i < gpuTimerQueries; i++ {
+		index := (t.next + i) % gpuTimerQueries
+		if !t.pending[index] {
+			continue
+		}
+
+		var ready int32
+		gl.GetQueryObjectiv(t.queries[index], gl.QUERY_RESULT_AVAILABLE, &ready)
+		if ready == gl.FALSE {
+			break
+		}
+
+		var elapsed uint64
+		gl.GetQueryObjectui64v(t.queries[index], gl.QUERY_RESULT, &elapsed)
+		t.last = time.Duration(elapsed)
+		t.pending[index] = false
+	}
+
+	return t.last, t.last > 0
+}
+
+func (t *GPUTimer) Delete() {
+	if t.available {
+		gl.DeleteQueries(gpuTimerQueries, &t.queries[0])
+		t.available = false
+	}
+}
@@ -0,0 +1,298 @@
+package opengl
+
+import "github.com/go-gl/glfw/v3.2/glfw"
+
+type InputData struct {
+	Mx, My     int
+	M1, M2, M3 bool
+	KeyMap     map[string]bool
+}
+
+// Snapshot of the input state, the KeyMap is a copy so is safe to keep
+func (w *Window) GetInputData() InputData {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	keyMap := make(map[string]bool, len(w.KeyMap))
+	for name, down := range w.KeyMap {
+		keyMap[name] = down
+	}
+
+	return InputData{
+		w.MouseX, w.MouseY,
+		w.Mouse1, w.Mouse2, w.Mouse3,
+		keyMap,
+	}
+}
+
+/*
+Edge triggered input, everything pressed, released or repeated since the previous PollInput.
+A tap shorter than a frame is reported as both pressed and released while never being held.
+Events collect in the pending frame as they arrive, PollInput swaps it in. glfw.PollEvents is
+global so events for other windows can arrive during this window's poll, they wait in that
+window's pending frame until its own PollInput.
+*/
+
+type inputFrame struct {
+	keysPressed, keysReleased, keysRepeated map[Key]bool
+	buttonsPressed, buttonsReleased         map[MouseButton]bool
+	text                                    []rune
+	scrollX, scrollY                        float64
+	deltaX, deltaY                          float64
+	gamepadPressed, gamepadReleased         map[gamepadButtonKey]bool
+	events                                  []Event // Only kept while recording
+}
+
+func newInputFrame() inputFrame {
+	return inputFrame{
+		make(map[Key]bool),
+		make(map[Key]bool),
+		make(map[Key]bool),
+		make(map[MouseButton]bool),
+		make(map[MouseButton]bool),
+		nil,
+		0, 0,
+		0, 0,
+		make(map[gamepadButtonKey]bool),
+		make(map[gamepadButtonKey]bool),
+		nil,
+	}
+}
+
+/*
+Process window and device events without ending the input frame, edges keep collecting in the
+pending frame until the next PollInput. glfw is pumped while playing back so the window stays
+responsive, live input is dropped.
+*/
+
+func (w *Window) PumpEvents() {
+	if w.GlWindow == nil {
+		return
+	}
+
+	glfw.PollEvents()
+	if !w.Playing() {
+		w.pollGamepads()
+	}
+}
+
+// Input handling, keys are updated by the glfw callbacks fired from PollEvents
+func (w *Window) PollInput() {
+	w.keyMutex.Lock()
+	w.frameCount++
+	frame, playback := w.frameCount, w.playback
+	w.keyMutex.Unlock()
+
+	w.PumpEvents()
+	if playback != nil {
+		w.replay(playback, frame)
+	}
+
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	w.frame = w.pending
+	w.pending = newInputFrame()
+
+	if w.recorder != nil {
+		for _, ev := range w.frame.events {
+			w.recorder.record(frame, ev)
+		}
+	}
+
+	//Get Mouse input
+	w.Mouse1 = w.buttons[MouseButtonLeft]
+	w.Mouse2 = w.buttons[MouseButtonRight]
+	w.Mouse3 = w.buttons[MouseButtonMiddle]
+
+	// Mapped every frame as the canvas can change without the cursor moving
+	mX, mY := w.WindowToCanvas(w.cursorX, w.cursorY)
+	w.MouseX, w.MouseY = int(mX), int(mY)
+}
+
+// Update the input state from an event
+func (w *Window) apply(ev Event) {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	switch ev.Type {
+	case EventKey:
+		down := ev.Action != ActionRelease
+		w.keys[ev.Key] = down
+		if name, ok := keyNames[ev.Key]; ok {
+			w.KeyMap[name] = down
+		}
+
+		switch ev.Action {
+		case ActionPress:
+			w.pending.keysPressed[ev.Key] = true
+		case ActionRelease:
+			w.pending.keysReleased[ev.Key] = true
+		case ActionRepeat:
+			w.pending.keysRepeated[ev.Key] = true
+		}
+	case EventChar:
+		w.pending.text = append(w.pending.text, ev.Char)
+	case EventMouseMove:
+		w.pending.deltaX += ev.X - w.cursorX
+		w.pending.deltaY += ev.Y - w.cursorY
+		w.cursorX, w.cursorY = ev.X, ev.Y
+	case EventScroll:
+		w.pending.scrollX += ev.X
+		w.pending.scrollY += ev.Y
+	case EventGamepadConnected:
+		w.gamepads[ev.Joystick] = &gamepadState{name: ev.Name}
+	case EventGamepadDisconnected:
+		delete(w.gamepads, ev.Joystick)
+	case EventGamepadButton:
+		if state, ok := w.gamepads[ev.Joystick]; ok {
+			state.buttons[ev.GamepadButton] = ev.Action == ActionPress
+		}
+
+		key := gamepadButtonKey{ev.Joystick, ev.GamepadButton}
+		if ev.Action == ActionPress {
+			w.pending.gamepadPressed[key] = true
+		} else {
+			w.pending.gamepadReleased[key] = true
+		}
+	case EventGamepadAxis:
+		if state, ok := w.gamepads[ev.Joystick]; ok {
+			state.axes[ev.GamepadAxis] = ev.Value
+		}
+	case EventMouseButton:
+		w.buttons[ev.Button] = ev.Action == ActionPress
+
+		if ev.Action == ActionPress {
+			w.pending.buttonsPressed[ev.Button] = true
+		} else {
+			w.pending.buttonsReleased[ev.Button] = true
+		}
+	}
+}
+
+// Keyboard state
+
+// Key by name, e.g "w" or "left_shift", unknown names are never held
+func (w *Window) Key(key string) bool {
+	k, ok := KeyFromName(key)
+	if !ok {
+		return false
+	}
+
+	return w.KeyHeld(k)
+}
+
+func (w *Window) KeyCombo(keys ...string) bool {
+	for _, key := range keys {
+		if !w.Key(key) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (w *Window) KeyHeld(key Key) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.keys[key]
+}
+
+func (w *Window) KeyComboHeld(keys ...Key) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	for _, key := range keys {
+		if !w.keys[key] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (w *Window) KeyPressed(key Key) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.keysPressed[key]
+}
+
+func (w *Window) KeyReleased(key Key) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.keysReleased[key]
+}
+
+// Key held long enough for the OS to send a repeat
+func (w *Window) KeyRepeated(key Key) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.keysRepeated[key]
+}
+
+// Characters typed since the previous PollInput
+func (w *Window) TextInput() string {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return string(w.frame.text)
+}
+
+// Clipboard
+
+func (w *Window) Clipboard() string {
+	text, err := w.GlWindow.GetClipboardString()
+	if err != nil {
+		return ""
+	}
+
+	return text
+}
+
+func (w *Window) SetClipboard(text string) {
+	w.GlWindow.SetClipboardString(text)
+}
+
+// Mouse button state
+
+func (w *Window) MouseHeld(button MouseButton) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.buttons[button]
+}
+
+func (w *Window) MousePressed(button MouseButton) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.buttonsPressed[button]
+}
+
+func (w *Window) MouseReleased(button MouseButton) bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.buttonsReleased[button]
+}
+
+// Modifiers currently held, from either the left or right key
+func (w *Window) Mods() ModifierKey {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	var mods ModifierKey
+	if w.keys[KeyLeftShift] || w.keys[KeyRightShift] {
+		mods |= ModShift
+	}
+	if w.keys[KeyLeftControl] || w.keys[KeyRightControl] {
+		mods |= ModControl
+	}
+	if w.keys[KeyLeftAlt] || w.keys[KeyRightAlt] {
+		mods |= ModAlt
+	}
+	if w.keys[KeyLeftSuper] || w.keys[KeyRightSuper] {
+		mods |= ModSuper
+	}
+
+	return mods
+}
+
+func (w *Window) ModsHeld(mods ModifierKey) bool {
+	return w.Mods()&mods == mods
+}
@@ -0,0 +1,88 @@
+package opengl
+
+import (
+	"image"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+type CursorMode int
+
+const (
+	CursorNormal CursorMode = iota
+	CursorHidden
+	// Hidden and locked to the window, the position is unbounded so use MouseDelta
+	CursorLocked
+)
+
+type StandardCursor int
+
+const (
+	ArrowCursor     StandardCursor = StandardCursor(glfw.ArrowCursor)
+	IBeamCursor     StandardCursor = StandardCursor(glfw.IBeamCursor)
+	CrosshairCursor StandardCursor = StandardCursor(glfw.CrosshairCursor)
+	HandCursor      StandardCursor = StandardCursor(glfw.HandCursor)
+	HResizeCursor   StandardCursor = StandardCursor(glfw.HResizeCursor)
+	VResizeCursor   StandardCursor = StandardCursor(glfw.VResizeCursor)
+)
+
+// Mouse position in canvas pixels with 0,0 at the bottom left
+func (w *Window) MousePosition() (float32, float32) {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.WindowToCanvas(w.cursorX, w.cursorY)
+}
+
+// Cursor movement since the previous PollInput in window coordinates, y grows downwards
+func (w *Window) MouseDelta() (float64, float64) {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.deltaX, w.frame.deltaY
+}
+
+// Scroll since the previous PollInput, positive y is scrolling up
+func (w *Window) Scroll() (float64, float64) {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frame.scrollX, w.frame.scrollY
+}
+
+/*
+Raw (unaccelerated) motion is not available with glfw 3.2, the locked mode gives unbounded
+relative motion through MouseDelta instead.
+*/
+
+func (w *Window) SetCursorMode(mode CursorMode) {
+	switch mode {
+	case CursorNormal:
+		w.GlWindow.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	case CursorHidden:
+		w.GlWindow.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+	case CursorLocked:
+		w.GlWindow.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+	}
+}
+
+// Cursor images
+
+// Hot spot is the click point in image pixels from the top left
+func (w *Window) SetCursorImage(img image.Image, hotX, hotY int) {
+	w.setCursor(glfw.CreateCursor(img, hotX, hotY))
+}
+
+func (w *Window) SetStandardCursor(shape StandardCursor) {
+	w.setCursor(glfw.CreateStandardCursor(glfw.StandardCursor(shape)))
+}
+
+func (w *Window) ResetCursor() {
+	w.setCursor(nil)
+}
+
+func (w *Window) setCursor(cursor *glfw.Cursor) {
+	w.GlWindow.SetCursor(cursor)
+
+	if w.cursor != nil {
+		w.cursor.Destroy()
+	}
+	w.cursor = cursor
+}
@@ -3,8 +3,8 @@ package opengl
 import "github.com/go-gl/gl/v4.1-core/gl"
 
 var (
-	freeVaos []uint32
-	vaoFree  []bool
+	glInitialised bool
+	currentWindow *Window
 )
 
 const (
@@ -18,22 +18,61 @@ func GlInit() {
 		panic(err)
 	}
 
+	glInitialised = true
+
+	if currentWindow != nil {
+		currentWindow.initContext()
+	}
+}
+
+/*
+Contexts share textures, shaders and buffers but not VAOs or GL state, so each window
+sets up its own state and VAO id pool the first time it is made current after GlInit.
+*/
+
+func (w *Window) initContext() {
+	if w.contextReady {
+		return
+	}
+	w.contextReady = true
+
+	w.viewportDirty = true
+	w.state.invalidate()
+
+	// Alpha blending so vertex colours can fade sprites
+	SetBlend(true)
+	SetBlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+
 	// Workaround for non-uniqueness on MacOS, halves GPU usage.
-	freeVaos = make([]uint32, MaxVAO)
-	vaoFree = make([]bool, MaxVAO)
-	gl.GenVertexArrays(MaxVAO, &freeVaos[0])
-	for i := range vaoFree {
-		vaoFree[i] = true
+	w.freeVaos = make([]uint32, MaxVAO)
+	w.vaoFree = make([]bool, MaxVAO)
+	gl.GenVertexArrays(MaxVAO, &w.freeVaos[0])
+	for i := range w.vaoFree {
+		w.vaoFree[i] = true
 	}
 }
 
+// Uses the current window's pool
 func GetVAOId() uint32 {
-	for i, free := range vaoFree {
+	return currentWindow.GetVAOId()
+}
+
+func (w *Window) GetVAOId() uint32 {
+	for i, free := range w.vaoFree {
 		if free {
-			vaoFree[i] = false
-			return freeVaos[i]
+			w.vaoFree[i] = false
+			return w.freeVaos[i]
 		}
 	}
 
 	panic("No free VAO id's remain")
 }
+
+func (w *Window) FreeVAOId(id uint32) {
+	for i, vaoId := range w.freeVaos {
+		if vaoId == id {
+			w.vaoFree[i] = true
+			return
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package opengl
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+/*
+Input recording and playback. A recorder logs every dispatched event with the frame PollInput
+made it visible in as one JSON object per line. A playback feeds those events back from PollInput
+in place of live input, so a session can be replayed deterministically, including on a headless
+window in tests. Frames are counted from when the recorder or playback was set on the window.
+*/
+
+type RecordedEvent struct {
+	Frame uint64 `json:"frame"`
+	Event Event  `json:"event"`
+}
+
+type Recorder struct {
+	mutex   sync.Mutex
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	closer  io.Closer
+	start   uint64
+	err     error
+}
+
+func NewRecorder(writer io.Writer) *Recorder {
+	buffered := bufio.NewWriter(writer)
+	return &Recorder{
+		writer:  buffered,
+		encoder: json.NewEncoder(buffered),
+	}
+}
+
+func CreateRecording(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := NewRecorder(file)
+	recorder.closer = file
+	return recorder, nil
+}
+
+func (r *Recorder) record(frame uint64, ev Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Keep the first error, the rest of the recording would be unusable anyway
+	if r.err != nil {
+		return
+	}
+	r.err = r.encoder.Encode(RecordedEvent{frame - r.start, ev})
+}
+
+// Flushes the recording and closes the file if created by CreateRecording
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.writer.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.closer = nil
+	}
+
+	return r.err
+}
+
+// Start recording from the next PollInput, nil stops recording without closing the recorder
+func (w *Window) SetRecorder(recorder *Recorder) {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	if recorder != nil {
+		recorder.start = w.frameCount + 1
+	}
+	w.recorder = recorder
+}
+
+type Playback struct {
+	events []RecordedEvent
+	next   int
+	start  uint64
+}
+
+func LoadPlayback(reader io.Reader) (*Playback, error) {
+	playback := &Playback{}
+	decoder := json.NewDecoder(reader)
+
+	for {
+		var ev RecordedEvent
+		if err := decoder.Decode(&ev); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		playback.events = append(playback.events, ev)
+	}
+
+	return playback, nil
+}
+
+func LoadPlaybackFile(path string) (*Playback, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadPlayback(file)
+}
+
+// Build a playback in code, events must be in frame order
+func NewPlayback(events []RecordedEvent) *Playback {
+	return &Playback{
+		events: events,
+	}
+}
+
+func (p *Playback) Done() bool {
+	return p.next >= len(p.events)
+}
+
+// Events for the given frame relative to the start of playback
+func (p *Playback) frameEvents(frame uint64) []Event {
+	var events []Event
+	for !p.Done() && p.events[p.next].Frame <= frame {
+		events = append(events, p.events[p.next].Event)
+		p.next++
+	}
+
+	return events
+}
+
+// Replace live input with playback from the next PollInput, live input resumes once it's done
+func (w *Window) SetPlayback(playback *Playback) {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+
+	if playback != nil {
+		playback.start = w.frameCount + 1
+	}
+	w.playback = playback
+}
+
+func (w *Window) Playing() bool {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.playback != nil
+}
+
+// Number of PollInput calls so far
+func (w *Window) Frame() uint64 {
+	w.keyMutex.Lock()
+	defer w.keyMutex.Unlock()
+	return w.frameCount
+}
+
+// Called from PollInput in place of polling glfw
+func (w *Window) replay(playback *Playback, frame uint64) {
+	for _, ev := range playback.frameEvents(frame - playback.start) {
+		// Resizes change the window size before being dispatched
+		switch ev.Type {
+		case EventResize:
+			w.sizeCallback(nil, ev.Width, ev.Height)
+		case EventFramebufferResize:
+			w.framebufferSizeCallback(nil, ev.Width, ev.Height)
+		default:
+			w.dispatch(ev)
+		}
+	}
+
+	if playback.Done() {
+		w.keyMutex.Lock()
+		if w.playback == playback {
+			w.playback = nil
+		}
+		w.keyMutex.Unlock()
+	}
+}
+
+/*
+Input only window without a glfw window or context, for replaying recorded input in tests.
+Only the input state, events and canvas mapping can be used.
+*/
+
+func CreateHeadlessWindow(width, height int) *Window {
+	return &Window{
+		Width:             float64(width),
+		Height:            float64(height),
+		FramebufferWidth:  width,
+		FramebufferHeight: height,
+		KeyMap:            make(map[string]bool),
+		keys:              make(map[Key]bool),
+		buttons:           make(map[MouseButton]bool),
+		gamepads:          make(map[Joystick]*gamepadState),
+		deadzone:          DefaultDeadzone,
+		frame:             newInputFrame(),
+		pending:           newInputFrame(),
+	}
+}
@@ -0,0 +1,94 @@
+package opengl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+type inputSnapshot struct {
+	held, pressed, released bool
+	text                    string
+	mouseX, mouseY          int
+	clicked                 bool
+	width, framebufferWidth int
+}
+
+func snapshot(w *Window) inputSnapshot {
+	return inputSnapshot{
+		held:             w.KeyHeld(KeyW),
+		pressed:          w.KeyPressed(KeyW),
+		released:         w.KeyReleased(KeyW),
+		text:             w.TextInput(),
+		mouseX:           w.MouseX,
+		mouseY:           w.MouseY,
+		clicked:          w.MousePressed(MouseButtonLeft),
+		width:            int(w.Width),
+		framebufferWidth: w.FramebufferWidth,
+	}
+}
+
+// Live input for each frame, sent through the glfw callbacks
+var recordedFrames = []func(w *Window){
+	func(w *Window) {
+		w.keyCallback(nil, glfw.KeyW, 0, glfw.Press, 0)
+		w.charModsCallback(nil, 'w', 0)
+	},
+	func(w *Window) {},
+	func(w *Window) {
+		w.cursorPosCallback(nil, 10, 20)
+		w.mouseButtonCallback(nil, glfw.MouseButtonLeft, glfw.Press, 0)
+	},
+	func(w *Window) {
+		w.keyCallback(nil, glfw.KeyW, 0, glfw.Release, 0)
+		w.sizeCallback(nil, 400, 300)
+		w.framebufferSizeCallback(nil, 400, 300)
+	},
+	func(w *Window) {
+		w.cursorPosCallback(nil, 100, 100)
+	},
+}
+
+func TestRecordPlaybackRoundTrip(t *testing.T) {
+	var recording bytes.Buffer
+	live := CreateHeadlessWindow(800, 600)
+	recorder := NewRecorder(&recording)
+	live.SetRecorder(recorder)
+
+	var want []inputSnapshot
+	for _, frame := range recordedFrames {
+		frame(live)
+		live.PollInput()
+		want = append(want, snapshot(live))
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	playback, err := LoadPlayback(&recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := CreateHeadlessWindow(800, 600)
+	replayed.SetPlayback(playback)
+
+	for i := range recordedFrames {
+		// Live input during playback is ignored
+		replayed.keyCallback(nil, glfw.KeyEscape, 0, glfw.Press, 0)
+
+		replayed.PollInput()
+		if got := snapshot(replayed); got != want[i] {
+			t.Errorf("frame %d: got %+v, want %+v", i, got, want[i])
+		}
+		if replayed.KeyHeld(KeyEscape) {
+			t.Errorf("frame %d: live input reached the window during playback", i)
+		}
+	}
+
+	if !playback.Done() || replayed.Playing() {
+		t.Error("playback did not finish")
+	}
+}
@@ -31,7 +31,7 @@ type shader struct {
 type Program struct {
 	Id         uint32
 	attributes map[string]uint32
-	uniforms   map[string]uniform
+	uniforms   map[string]*uniform
 }
 
 // Shader program loading and creation
@@ -44,7 +44,7 @@ func CreateProgram(Id uint32) *Program {
 	return &Program{
 		Id,
 		make(map[string]uint32),
-		make(map[string]uniform),
+		make(map[string]*uniform),
 	}
 }
 
@@ -130,11 +130,18 @@ func findShader(file string) *shader {
 // Shader binding and linking
 
 func (p *Program) Use() {
-	gl.UseProgram(p.Id)
+	if useProgram(p.Id) {
+		currentStats().ProgramSwitches++
+	}
 }
 
 func (p *Program) UnUse() {
-	gl.UseProgram(0)
+	useProgram(0)
+}
+
+func (p *Program) Delete() {
+	forgetProgram(p.Id)
+	gl.DeleteProgram(p.Id)
 }
 
 func (p *Program) Link() {
@@ -167,10 +174,22 @@ func (p *Program) DisableAttribute(attribute string) {
 
 // Uniform handling
 
+/*
+Uniforms hold a pointer to the value, the pointed to value is compared against the value last
+uploaded so only changed uniforms are sent to GL. The last value is kept typed, only the field
+for the uniform's type is used.
+*/
+
 type uniform struct {
-	id      uint32
-	value   interface{}
-	updated bool
+	id       uint32
+	value    interface{}
+	uploaded bool
+	lastF    float32
+	lastV2   mgl32.Vec2
+	lastV3   mgl32.Vec3
+	lastV4   mgl32.Vec4
+	lastM2   mgl32.Mat2
+	lastM3   mgl32.Mat3
 }
 
 func (uni *uniform) ID() uint32 {
@@ -181,92 +200,113 @@ func (uni *uniform) Value() interface{} {
 	return uni.value
 }
 
-/*
-Each Render call Attach is called for each uniform, Attach only sets the value if
-Update set
-*/
-
+// Force the value to be uploaded on the next Attach even if unchanged
 func (uni *uniform) Update() {
-	uni.updated = true
+	uni.uploaded = false
 }
 
+// Upload the value if it has changed, the program must be in use
 func (uni *uniform) Attach() {
-	// Updating uniforms is expensive, avoid if possible
-	// Or if an invalid uniform, i.e uint32(-1)
-	if !uni.updated || uni.id == 4294967295 {
+	// Invalid uniforms, i.e uint32(-1), are optimised out of the shader
+	if uni.id == 4294967295 {
 		return
 	}
 
-	uni.updated = false
-
-	switch uni.value.(type) {
+	location := int32(uni.id)
+	switch value := uni.value.(type) {
 	case *float32:
-		value := *(uni.value).(*float32)
-		gl.Uniform1f(int32(uni.id), value)
+		if uni.uploaded && *value == uni.lastF {
+			return
+		}
+		uni.lastF = *value
+		gl.Uniform1f(location, *value)
 	case *mgl32.Vec2:
-		value := *(uni.value).(*mgl32.Vec2)
-		gl.Uniform2f(int32(uni.id), value.X(), value.Y())
+		if uni.uploaded && *value == uni.lastV2 {
+			return
+		}
+		uni.lastV2 = *value
+		gl.Uniform2f(location, value.X(), value.Y())
 	case *mgl32.Vec3:
-		value := *(uni.value).(*mgl32.Vec3)
-		gl.Uniform3f(int32(uni.id), value.X(), value.Y(), value.Z())
+		if uni.uploaded && *value == uni.lastV3 {
+			return
+		}
+		uni.lastV3 = *value
+		gl.Uniform3f(location, value.X(), value.Y(), value.Z())
 	case *mgl32.Vec4:
-		value := *(uni.value).(*mgl32.Vec4)
-		gl.Uniform4f(int32(uni.id), value.X(), value.Y(), value.Z(), value.W())
+		if uni.uploaded && *value == uni.lastV4 {
+			return
+		}
+		uni.lastV4 = *value
+		gl.Uniform4f(location, value.X(), value.Y(), value.Z(), value.W())
 	case *mgl32.Mat2:
-		value := *(uni.value).(*mgl32.Mat2)
-		gl.UniformMatrix2fv(int32(uni.id), 1, false, &value[0])
+		if uni.uploaded && *value == uni.lastM2 {
+			return
+		}
+		uni.lastM2 = *value
+		gl.UniformMatrix2fv(location, 1, false, &uni.lastM2[0])
+	case *mgl32.Mat3:
+		if uni.uploaded && *value == uni.lastM3 {
+			return
+		}
+		uni.lastM3 = *value
+		gl.UniformMatrix3fv(location, 1, false, &uni.lastM3[0])
 	default:
 		panic("Unsupported uniform type, these should be pointers")
 	}
+
+	uni.uploaded = true
+	currentStats().UniformUploads++
 }
 
 func (p *Program) AddUniform(name string, value interface{}) {
-	uni := uniform{
-		uint32(gl.GetUniformLocation(p.Id, gl.Str(name+"\x00"))),
-		value,
-		true,
+	uni := &uniform{
+		id:    uint32(gl.GetUniformLocation(p.Id, gl.Str(name+"\x00"))),
+		value: value,
 	}
 	p.Use()
 	uni.Attach()
 	p.uniforms[name] = uni
 }
 
-/*
-Set uniform value, this should be set to pointers as this is an expensive way to update,
-if set to a pointer then call Update()
-*/
-
+// Point the uniform at a new value, it is uploaded immediately
 func (p *Program) SetUniform(name string, value interface{}) {
-	if _, exists := p.uniforms[name]; !exists {
+	uni, exists := p.uniforms[name]
+	if !exists {
 		panic("Attempting to set non existent uniform")
 	}
 
-	uni := uniform{
-		p.uniforms[name].id,
-		value,
-		true,
-	}
+	uni.value = value
+	uni.Update()
 	p.Use()
 	uni.Attach()
-	p.uniforms[name] = uni
 }
 
+// Upload a single uniform if its value has changed
 func (p *Program) UpdateUniform(name string) {
-	u, exists := p.uniforms[name]
+	uni, exists := p.uniforms[name]
 	if !exists {
 		panic("Attempting to set non existent uniform")
 	}
+
 	p.Use()
-	u.Update()
+	uni.Attach()
 }
 
 /*
-In general this should be called and should be called by the vao after PrepRender
+In general this should be called and should be called by the vao after PrepRender,
+only uniforms whose values changed since the last upload are sent.
 */
 func (p *Program) UpdateUniforms() {
 	p.Use()
 	for _, uni := range p.uniforms {
-		uni.Update()
 		uni.Attach()
 	}
 }
+
+// Upload every uniform regardless of changes, e.g after setting them with GL directly
+func (p *Program) RefreshUniforms() {
+	for _, uni := range p.uniforms {
+		uni.Update()
+	}
+	p.UpdateUniforms()
+}
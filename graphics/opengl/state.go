@@ -0,0 +1,174 @@
+package opengl
+
+import "github.com/go-gl/gl/v4.1-core/gl"
+
+/*
+Cache of the GL bindings for each window's context so redundant binds are skipped. Everything
+in this package binds through it, call InvalidateState after making GL calls directly so the
+next bind is always issued. With no current window calls go straight to GL.
+*/
+
+const unknownState = ^uint32(0)
+
+type glState struct {
+	program     uint32
+	vao         uint32
+	arrayBuffer uint32
+	activeUnit  uint32
+	textures    map[uint32]uint32 // Texture unit to bound texture
+	blend       int               // 0 unknown, 1 disabled, 2 enabled
+	blendSrc    uint32
+	blendDst    uint32
+}
+
+func (s *glState) invalidate() {
+	s.program = unknownState
+	s.vao = unknownState
+	s.arrayBuffer = unknownState
+	s.activeUnit = unknownState
+	s.textures = make(map[uint32]uint32)
+	s.blend = 0
+	s.blendSrc = unknownState
+	s.blendDst = unknownState
+}
+
+func currentState() *glState {
+	if currentWindow == nil {
+		return nil
+	}
+
+	return &currentWindow.state
+}
+
+// Forget everything bound in this window's context
+func (w *Window) InvalidateState() {
+	w.state.invalidate()
+}
+
+// Forget everything bound in the current context
+func InvalidateState() {
+	if s := currentState(); s != nil {
+		s.invalidate()
+	}
+}
+
+// Binding, true if the GL call was made
+
+func useProgram(id uint32) bool {
+	s := currentState()
+	if s != nil {
+		if s.program == id {
+			return false
+		}
+		s.program = id
+	}
+
+	gl.UseProgram(id)
+	return true
+}
+
+func bindVertexArray(id uint32) {
+	s := currentState()
+	if s != nil {
+		if s.vao == id {
+			return
+		}
+		s.vao = id
+	}
+
+	gl.BindVertexArray(id)
+}
+
+func bindArrayBuffer(id uint32) {
+	s := currentState()
+	if s != nil {
+		if s.arrayBuffer == id {
+			return
+		}
+		s.arrayBuffer = id
+	}
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, id)
+}
+
+// Unit is the GL enum, e.g gl.TEXTURE0
+func bindTexture(unit, id uint32) bool {
+	s := currentState()
+	if s == nil {
+		gl.ActiveTexture(unit)
+		gl.BindTexture(gl.TEXTURE_2D, id)
+		return true
+	}
+
+	if s.activeUnit != unit {
+		gl.ActiveTexture(unit)
+		s.activeUnit = unit
+	}
+
+	if bound, ok := s.textures[unit]; ok && bound == id {
+		return false
+	}
+	s.textures[unit] = id
+
+	gl.BindTexture(gl.TEXTURE_2D, id)
+	return true
+}
+
+// Deleted names can be reused by GL so must not be assumed to still be bound
+
+func forgetProgram(id uint32) {
+	if s := currentState(); s != nil && s.program == id {
+		s.program = unknownState
+	}
+}
+
+func forgetArrayBuffer(id uint32) {
+	if s := currentState(); s != nil && s.arrayBuffer == id {
+		s.arrayBuffer = unknownState
+	}
+}
+
+func forgetTexture(id uint32) {
+	if s := currentState(); s != nil {
+		for unit, bound := range s.textures {
+			if bound == id {
+				delete(s.textures, unit)
+			}
+		}
+	}
+}
+
+// Blending
+
+func SetBlend(enabled bool) {
+	state := 1
+	if enabled {
+		state = 2
+	}
+
+	s := currentState()
+	if s != nil {
+		if s.blend == state {
+			return
+		}
+		s.blend = state
+	}
+
+	if enabled {
+		gl.Enable(gl.BLEND)
+	} else {
+		gl.Disable(gl.BLEND)
+	}
+}
+
+func SetBlendFunc(src, dst uint32) {
+	s := currentState()
+	if s != nil {
+		if s.blendSrc == src && s.blendDst == dst {
+			return
+		}
+		s.blendSrc, s.blendDst = src, dst
+	}
+
+	gl.BlendFunc(src, dst)
+}
@@ -0,0 +1,41 @@
+package opengl
+
+/*
+Counts of GL calls per frame for the window whose context is current. The frame ends at
+SwapBuffers, DrawStats then returns the totals for that frame. Counted in the opengl thread.
+*/
+
+type DrawStats struct {
+	DrawCalls       int
+	Vertices        int
+	ProgramSwitches int
+	TextureBinds    int
+	BufferUploads   int
+	BufferBytes     int
+	UniformUploads  int
+}
+
+type drawCounters struct {
+	current, last DrawStats
+}
+
+// Counts made while no window is current are discarded
+var discardedStats DrawStats
+
+func currentStats() *DrawStats {
+	if currentWindow == nil {
+		return &discardedStats
+	}
+
+	return &currentWindow.drawCounters.current
+}
+
+func (w *Window) endDrawStats() {
+	w.drawCounters.last = w.drawCounters.current
+	w.drawCounters.current = DrawStats{}
+}
+
+// Totals for the last frame swapped to the window
+func (w *Window) DrawStats() DrawStats {
+	return w.drawCounters.last
+}
@@ -41,7 +41,7 @@ type Texture struct {
 	width       int
 	height      int
 	file        string
-	textureUnit uint32
+	textureUnit uint32 // GL enum, e.g gl.TEXTURE0
 }
 
 /*
@@ -76,9 +76,9 @@ func LoadTexture(file string) *Texture {
 	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
 
 	var texture uint32
-	gl.ActiveTexture(currentTextureUnit())
+	unit := currentTextureUnit()
 	gl.GenTextures(1, &texture)
-	gl.BindTexture(gl.TEXTURE_2D, texture)
+	bindTexture(unit, texture)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
@@ -99,10 +99,10 @@ func LoadTexture(file string) *Texture {
 		bounds.Max.X,
 		bounds.Max.Y,
 		file,
-		currentTextureUnitId,
+		unit,
 	}
 
-	gl.BindTexture(gl.TEXTURE_2D, 0)
+	bindTexture(unit, 0)
 
 	//Add texture to texture store
 	storedTextures = append(storedTextures, textureObj)
@@ -125,8 +125,9 @@ Texture usage methods
 */
 
 func (t *Texture) Use() {
-	gl.ActiveTexture(t.textureUnit)
-	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	if bindTexture(t.textureUnit, t.id) {
+		currentStats().TextureBinds++
+	}
 }
 
 // NormCoords ... normalize pixture texture coordinates
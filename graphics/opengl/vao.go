@@ -35,14 +35,18 @@ type Buffer struct {
 	created   bool
 	attribute string
 	vao       VAO
+	changed   bool
 }
 
 // VAO creation and destruction
 func CreateVAO(window *Window, textureSource string) *BaseVAO {
+	// VAOs belong to a single context
+	window.MakeCurrent()
+
 	texture := LoadTexture(textureSource)
 
 	vao := BaseVAO{
-		id:       GetVAOId(),
+		id:       window.GetVAOId(),
 		window:   window,
 		texture:  texture,
 		buffers:  make(map[string]*Buffer),
@@ -61,15 +65,20 @@ func (vao *BaseVAO) Init() {
 }
 
 func (vao *BaseVAO) Delete() {
+	vao.window.MakeCurrent()
+	vao.BindVao()
 	for _, b := range vao.buffers {
+		vao.shader.DisableAttribute(b.attribute)
 		b.Delete()
 	}
+	bindVertexArray(0)
 
-	gl.DeleteVertexArrays(1, &vao.id)
+	// Ids come from a pre-generated pool, return it rather than deleting it
+	vao.window.FreeVAOId(vao.id)
 }
 
 func (vao *BaseVAO) BindVao() {
-	gl.BindVertexArray(vao.id)
+	bindVertexArray(vao.id)
 }
 
 func (vao *BaseVAO) AddBuffer(id string, buffer *Buffer) {
@@ -83,18 +92,33 @@ func (vao *BaseVAO) GetBuffer(id string) *Buffer {
 }
 
 func (vao *BaseVAO) UpdateBuffers() {
+	vao.window.MakeCurrent()
 	vao.BindVao()
 
 	for _, b := range vao.buffers {
 		b.Update()
 	}
 
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	bindArrayBuffer(0)
+}
+
+// Upload only buffers marked changed, and any not yet created
+func (vao *BaseVAO) UpdateChangedBuffers() {
+	vao.window.MakeCurrent()
+	vao.BindVao()
+
+	for _, b := range vao.buffers {
+		if b.changed || !b.created {
+			b.Update()
+		}
+	}
+
+	bindArrayBuffer(0)
 }
 
 func (vao *BaseVAO) UpdateBuffer(name string) {
 	vao.buffers[name].Update()
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	bindArrayBuffer(0)
 }
 
 // Buffer Updating
@@ -109,7 +133,9 @@ func (buffer *Buffer) Create() {
 	gl.GenBuffers(1, &buffer.ID)
 
 	// Set buffer data
-	gl.BindBuffer(gl.ARRAY_BUFFER, buffer.ID)
+	bindArrayBuffer(buffer.ID)
+	buffer.countUpload()
+	buffer.changed = false
 	gl.BufferData(gl.ARRAY_BUFFER, 4*len(buffer.Elements), gl.Ptr(buffer.Elements), gl.DYNAMIC_DRAW)
 
 	//Setup attribute pointer
@@ -118,15 +144,31 @@ func (buffer *Buffer) Create() {
 }
 
 func (buffer *Buffer) Update() {
+	// Creating uploads the elements
 	if !buffer.created {
 		buffer.Create()
+		return
 	}
 
-	gl.BindBuffer(gl.ARRAY_BUFFER, buffer.ID)
+	bindArrayBuffer(buffer.ID)
+	buffer.countUpload()
+	buffer.changed = false
 	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(buffer.Elements), gl.Ptr(buffer.Elements))
 }
 
+// Mark the elements as edited for UpdateChangedBuffers
+func (buffer *Buffer) MarkChanged() {
+	buffer.changed = true
+}
+
+func (buffer *Buffer) countUpload() {
+	stats := currentStats()
+	stats.BufferUploads++
+	stats.BufferBytes += 4 * len(buffer.Elements)
+}
+
 func (buffer *Buffer) Delete() {
+	forgetArrayBuffer(buffer.ID)
 	gl.DeleteBuffers(1, &buffer.ID)
 	buffer.created = false
 }
@@ -152,6 +194,10 @@ func (vao *BaseVAO) UpdateUniforms() {
 	vao.shader.UpdateUniforms()
 }
 
+func (vao *BaseVAO) RefreshUniforms() {
+	vao.shader.RefreshUniforms()
+}
+
 func (vao *BaseVAO) PixToTex(texX, texY int) (float32, float32) {
 	return vao.texture.PixToTex(texX, texY)
 }
@@ -173,7 +219,12 @@ func (vao *BaseVAO) VertNum() int32 {
 }
 
 func (vao *BaseVAO) Render() {
-	gl.DrawArrays(gl.TRIANGLES, 0, vao.VertNum())
+	vertices := vao.VertNum()
+	stats := currentStats()
+	stats.DrawCalls++
+	stats.Vertices += int(vertices)
+
+	gl.DrawArrays(gl.TRIANGLES, 0, vertices)
 }
 
 func RenderVaos(vaos []VAO) {
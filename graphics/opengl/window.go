@@ -1,120 +1,291 @@
 package opengl
 
 import (
+	"image"
 	"sync"
 
+	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
+/*
+Width and Height are in window coordinates, the pixel space render objects and the mouse use.
+On HiDPI displays the framebuffer can be larger, FramebufferWidth/Height are the actual pixels.
+*/
+
 type Window struct {
-	GlWindow      *glfw.Window
-	Width, Height float64
-	Name          string
+	GlWindow                            *glfw.Window
+	Width, Height                       float64
+	FramebufferWidth, FramebufferHeight int
+	Name                                string
+	Resizable                           bool
+	Samples                             int
+	resizeCallbacks                     []func(width, height int)
+
+	// Virtual resolution, zero when rendering at the window size
+	virtualWidth, virtualHeight int
+	integerScaling              bool
+
+	// Restored when leaving fullscreen
+	windowedX, windowedY          int
+	windowedWidth, windowedHeight int
+
+	// Per context state
+	contextReady  bool
+	viewportDirty bool
+	freeVaos      []uint32
+	vaoFree       []bool
+	drawCounters  drawCounters
+	state         glState
+
+	// Draws offscreen framebuffers to the window, created on first use
+	presentProgram *Program
+	presentVao     uint32
 
-	// Mouse and keyboard
+	// Mouse and keyboard, KeyMap mirrors keys by name
 	keyMutex               sync.Mutex
 	KeyMap                 map[string]bool
-	MouseX, MouseY         int
-	Mouse1, Mouse2, Mouse3 bool
-}
+	keys                   map[Key]bool
+	buttons                map[MouseButton]bool
+	frame, pending         inputFrame
+	subscribers            eventSubscribers
+	MouseX, MouseY         int  // Canvas pixels, 0,0 bottom left
+	Mouse1, Mouse2, Mouse3 bool // Left, right and middle buttons
+	cursorX, cursorY       float64
+	cursor                 *glfw.Cursor
+	gamepads               map[Joystick]*gamepadState
+	deadzone               float32
 
-type InputData struct {
-	Mx, My     int
-	M1, M2, M3 bool
-	KeyMap     map[string]bool
+	// Recording and playback, see recording.go
+	frameCount uint64
+	recorder   *Recorder
+	playback   *Playback
 }
 
 // Window Creation and destruction
 
 func CreateWindow(width, height int, name string) *Window {
+	return CreateWindowFromConfig(DefaultWindowConfig(width, height, name))
+}
+
+func CreateResizableWindow(width, height int, name string) *Window {
+	config := DefaultWindowConfig(width, height, name)
+	config.Resizable = true
+
+	return CreateWindowFromConfig(config)
+}
+
+// Shares textures, shaders and buffers with share, VAOs and GL state are per window
+func CreateSharedWindow(width, height int, name string, share *Window) *Window {
+	config := DefaultWindowConfig(width, height, name)
+	config.Share = share
+
+	return CreateWindowFromConfig(config)
+}
+
+func CreateWindowFromConfig(config WindowConfig) *Window {
 	if err := glfw.Init(); err != nil {
 		panic(err)
 	}
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	glfw.DefaultWindowHints()
+	glfw.WindowHint(glfw.Resizable, glfwBool(config.Resizable))
+	glfw.WindowHint(glfw.Decorated, glfwBool(!config.Borderless))
+	glfw.WindowHint(glfw.Samples, config.Samples)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-	window, err := glfw.CreateWindow(width, height, name, nil, nil)
+	var shareWindow *glfw.Window
+	if config.Share != nil {
+		shareWindow = config.Share.GlWindow
+	}
+
+	monitor := getMonitor(config.Monitor)
+	width, height := config.Width, config.Height
+	var fullscreenMonitor *glfw.Monitor
+
+	if config.Fullscreen {
+		mode := findVideoMode(monitor, config.VideoMode)
+		glfw.WindowHint(glfw.RefreshRate, mode.RefreshRate)
+		width, height = mode.Width, mode.Height
+		fullscreenMonitor = monitor
+	} else if config.Borderless && (width == 0 || height == 0) {
+		// Borderless windowed covering the whole monitor
+		mode := monitor.GetVideoMode()
+		width, height = mode.Width, mode.Height
+		if config.Position == nil {
+			x, y := monitor.GetPos()
+			config.Position = &image.Point{x, y}
+		}
+	}
+
+	window, err := glfw.CreateWindow(width, height, config.Name, fullscreenMonitor, shareWindow)
 
 	if err != nil {
 		panic(err)
 	}
 
-	window.MakeContextCurrent()
+	if config.Position != nil && !config.Fullscreen {
+		window.SetPos(config.Position.X, config.Position.Y)
+	}
 
-	w := Window{
-		keyMutex: sync.Mutex{},
-		GlWindow: window,
-		Width:    float64(width),
-		Height:   float64(height),
-		Name:     name,
-		KeyMap:   make(map[string]bool),
+	if len(config.Icon) > 0 {
+		window.SetIcon(config.Icon)
 	}
 
-	return &w
-}
+	w := &Window{
+		keyMutex:  sync.Mutex{},
+		GlWindow:  window,
+		Width:     float64(width),
+		Height:    float64(height),
+		Name:      config.Name,
+		Resizable: config.Resizable,
+		Samples:   config.Samples,
+		KeyMap:    make(map[string]bool),
+		keys:      make(map[Key]bool),
+		buttons:   make(map[MouseButton]bool),
+		gamepads:  make(map[Joystick]*gamepadState),
+		deadzone:  DefaultDeadzone,
+		frame:     newInputFrame(),
+		pending:   newInputFrame(),
+	}
 
-func DestroyWindow(window *glfw.Window) {
-	window.Destroy()
+	w.windowedX, w.windowedY = window.GetPos()
+	w.windowedWidth, w.windowedHeight = window.GetSize()
+	if config.Fullscreen {
+		w.setWindowedSize(monitor, config.Width, config.Height)
+	}
+	w.FramebufferWidth, w.FramebufferHeight = window.GetFramebufferSize()
+	w.cursorX, w.cursorY = window.GetCursorPos()
+	window.SetSizeCallback(w.sizeCallback)
+	window.SetFramebufferSizeCallback(w.framebufferSizeCallback)
+	w.setEventCallbacks()
+
+	w.MakeCurrent()
+	glfw.SwapInterval(config.SwapInterval)
+
+	return w
 }
 
-func (w *Window) SwapBuffers() {
-	w.GlWindow.SwapBuffers()
+// Size to restore to when leaving fullscreen, a zero size uses half the monitor centred on it
+func (w *Window) setWindowedSize(monitor *glfw.Monitor, width, height int) {
+	if width > 0 && height > 0 {
+		w.windowedWidth, w.windowedHeight = width, height
+		return
+	}
+
+	mode := monitor.GetVideoMode()
+	x, y := monitor.GetPos()
+	w.windowedWidth, w.windowedHeight = mode.Width/2, mode.Height/2
+	w.windowedX, w.windowedY = x+mode.Width/4, y+mode.Height/4
 }
 
-func (w *Window) ShouldClose() bool {
-	return w.GlWindow.ShouldClose()
+// Resize handling, callbacks are called from PollInput in the opengl thread
+
+func (w *Window) sizeCallback(_ *glfw.Window, width, height int) {
+	// Minimizing reports a zero size, keep the last usable one
+	if width == 0 || height == 0 {
+		return
+	}
+
+	w.Width = float64(width)
+	w.Height = float64(height)
+
+	for _, callback := range w.resizeCallbacks {
+		callback(width, height)
+	}
+
+	w.dispatch(Event{
+		Type:   EventResize,
+		Width:  width,
+		Height: height,
+	})
 }
 
-func (w *Window) GetInputData() InputData {
-	return InputData{
-		w.MouseX, w.MouseY,
-		w.Mouse1, w.Mouse2, w.Mouse3,
-		w.KeyMap,
+func (w *Window) framebufferSizeCallback(_ *glfw.Window, width, height int) {
+	// Minimizing reports a zero size, keep the last usable one
+	if width == 0 || height == 0 {
+		return
 	}
+
+	w.FramebufferWidth = width
+	w.FramebufferHeight = height
+	w.viewportDirty = true
+
+	w.dispatch(Event{
+		Type:   EventFramebufferResize,
+		Width:  width,
+		Height: height,
+	})
 }
 
-// Input handling
-func (w *Window) PollInput() {
-	w.keyMutex.Lock()
-	defer w.keyMutex.Unlock()
-	glfw.PollEvents()
-	window := w.GlWindow
+// Called with the new size in window coordinates
+func (w *Window) OnResize(callback func(width, height int)) {
+	w.resizeCallbacks = append(w.resizeCallbacks, callback)
+}
+
+// Resize the viewport to the framebuffer if it has changed, requires the context to be current
+func (w *Window) UpdateViewport() {
+	if !w.viewportDirty {
+		return
+	}
 
-	//Get Keyboard input
-	w.KeyMap["w"] = window.GetKey(glfw.KeyW) == glfw.Press
-	w.KeyMap["a"] = window.GetKey(glfw.KeyA) == glfw.Press
-	w.KeyMap["s"] = window.GetKey(glfw.KeyS) == glfw.Press
-	w.KeyMap["d"] = window.GetKey(glfw.KeyD) == glfw.Press
+	gl.Viewport(0, 0, int32(w.FramebufferWidth), int32(w.FramebufferHeight))
+	w.viewportDirty = false
+}
 
-	//Get Mouse input
-	w.Mouse1 = window.GetMouseButton(glfw.MouseButtonRight) == glfw.Press
-	w.Mouse2 = window.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press
-	w.Mouse3 = window.GetMouseButton(glfw.MouseButtonMiddle) == glfw.Press
+// Force the viewport back to the whole framebuffer, e.g after rendering offscreen
+func (w *Window) ResetViewport() {
+	w.viewportDirty = true
+	w.UpdateViewport()
+}
 
-	mX, mY := window.GetCursorPos()
+// Framebuffer pixels per window coordinate, greater than 1 on HiDPI displays
+func (w *Window) ContentScale() (float32, float32) {
+	if w.Width == 0 || w.Height == 0 {
+		return 1, 1
+	}
 
-	w.MouseX, w.MouseY = w.ScreenToPix(float32(mX), float32(mY))
+	return float32(w.FramebufferWidth) / float32(w.Width), float32(w.FramebufferHeight) / float32(w.Height)
 }
 
-func (w *Window) Key(key string) bool {
-	w.keyMutex.Lock()
-	defer w.keyMutex.Unlock()
-	return w.KeyMap[key]
+func DestroyWindow(window *glfw.Window) {
+	window.Destroy()
 }
 
-func (w *Window) KeyCombo(keys ...string) bool {
-	w.keyMutex.Lock()
-	defer w.keyMutex.Unlock()
-	for _, key := range keys {
-		if !w.Key(key) {
-			return false
-		}
+func (w *Window) Destroy() {
+	if currentWindow == w {
+		currentWindow = nil
+	}
+	w.GlWindow.Destroy()
+}
+
+// Make this window's context current on the calling thread, a no-op if it already is
+func (w *Window) MakeCurrent() {
+	if currentWindow == w {
+		return
 	}
 
-	return true
+	w.GlWindow.MakeContextCurrent()
+	currentWindow = w
+
+	if glInitialised {
+		w.initContext()
+	}
+}
+
+func CurrentWindow() *Window {
+	return currentWindow
+}
+
+func (w *Window) SwapBuffers() {
+	w.endDrawStats()
+	w.GlWindow.SwapBuffers()
+}
+
+func (w *Window) ShouldClose() bool {
+	return w.GlWindow.ShouldClose()
 }
 
 // Pixel to Screen coordinate conversion
@@ -0,0 +1,191 @@
+package opengl
+
+import (
+	"image"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+type VideoMode struct {
+	Width, Height, RefreshRate int
+}
+
+/*
+Borderless without fullscreen creates an undecorated window, with a zero width or height
+it covers the whole of the chosen monitor (borderless windowed).
+*/
+
+type WindowConfig struct {
+	Width, Height int
+	Name          string
+	Resizable     bool
+	Fullscreen    bool
+	Borderless    bool
+	Monitor       int        // Index into Monitors(), 0 is the primary monitor
+	VideoMode     *VideoMode // Fullscreen mode, nil uses the monitor's current mode
+	SwapInterval  int        // 1 for vsync, 0 to swap immediately
+	Samples       int        // MSAA samples, 0 disables
+	Icon          []image.Image
+	Position      *image.Point // nil lets the window manager place the window
+	Share         *Window      // Share textures, shaders and buffers with this window
+}
+
+func DefaultWindowConfig(width, height int, name string) WindowConfig {
+	return WindowConfig{
+		Width:        width,
+		Height:       height,
+		Name:         name,
+		SwapInterval: 1,
+	}
+}
+
+// Monitors
+
+// Names of the connected monitors, the primary monitor is first
+func Monitors() []string {
+	if err := glfw.Init(); err != nil {
+		panic(err)
+	}
+
+	var names []string
+	for _, m := range monitors() {
+		names = append(names, m.GetName())
+	}
+
+	return names
+}
+
+func MonitorVideoModes(monitor int) []VideoMode {
+	if err := glfw.Init(); err != nil {
+		panic(err)
+	}
+
+	var modes []VideoMode
+	for _, mode := range getMonitor(monitor).GetVideoModes() {
+		modes = append(modes, VideoMode{mode.Width, mode.Height, mode.RefreshRate})
+	}
+
+	return modes
+}
+
+func monitors() []*glfw.Monitor {
+	primary := glfw.GetPrimaryMonitor()
+	list := []*glfw.Monitor{primary}
+
+	for _, m := range glfw.GetMonitors() {
+		if !sameMonitor(m, primary) {
+			list = append(list, m)
+		}
+	}
+
+	return list
+}
+
+func getMonitor(index int) *glfw.Monitor {
+	list := monitors()
+	if index < 0 || index >= len(list) {
+		panic("Invalid monitor index given")
+	}
+
+	return list[index]
+}
+
+// glfw wraps the monitor handle in a new struct on every call, so compare the handles
+func sameMonitor(a, b *glfw.Monitor) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// Closest supported mode to the requested one, the current mode if none is requested
+func findVideoMode(monitor *glfw.Monitor, requested *VideoMode) VideoMode {
+	current := monitor.GetVideoMode()
+	if requested == nil {
+		return VideoMode{current.Width, current.Height, current.RefreshRate}
+	}
+
+	best := VideoMode{current.Width, current.Height, current.RefreshRate}
+	bestScore := -1
+	for _, mode := range monitor.GetVideoModes() {
+		score := abs(mode.Width-requested.Width) + abs(mode.Height-requested.Height)
+		if requested.RefreshRate != 0 {
+			score += abs(mode.RefreshRate - requested.RefreshRate)
+		}
+
+		if bestScore == -1 || score < bestScore {
+			best = VideoMode{mode.Width, mode.Height, mode.RefreshRate}
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+// Runtime fullscreen toggling
+
+func (w *Window) Fullscreen() bool {
+	return w.GlWindow.GetMonitor() != nil
+}
+
+// Switch to fullscreen on the given monitor using its current video mode, moving between
+// monitors if already fullscreen on another one
+func (w *Window) SetFullscreen(fullscreen bool, monitor int) {
+	current := w.GlWindow.GetMonitor()
+
+	if fullscreen {
+		m := getMonitor(monitor)
+		if sameMonitor(current, m) {
+			return
+		}
+
+		// Only save the windowed placement when leaving windowed mode, not when changing monitor
+		if current == nil {
+			w.windowedX, w.windowedY = w.GlWindow.GetPos()
+			w.windowedWidth, w.windowedHeight = w.GlWindow.GetSize()
+		}
+
+		mode := m.GetVideoMode()
+		w.GlWindow.SetMonitor(m, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+		return
+	}
+
+	if current == nil {
+		return
+	}
+
+	w.GlWindow.SetMonitor(nil, w.windowedX, w.windowedY, w.windowedWidth, w.windowedHeight, 0)
+}
+
+func (w *Window) ToggleFullscreen() {
+	w.SetFullscreen(!w.Fullscreen(), 0)
+}
+
+func (w *Window) SetIcon(icon []image.Image) {
+	w.GlWindow.SetIcon(icon)
+}
+
+// Swap interval is per context, this makes the window current
+func (w *Window) SetSwapInterval(interval int) {
+	w.MakeCurrent()
+	glfw.SwapInterval(interval)
+}
+
+// Util
+
+func glfwBool(b bool) int {
+	if b {
+		return glfw.True
+	}
+
+	return glfw.False
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
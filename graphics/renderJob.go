@@ -1,15 +1,12 @@
 package graphics
 
-var (
-	renderJobs = make(chan *RenderJob, 100)
-)
-
 // Async opengl sounds likes a great idea :)
 
 type RenderJob struct {
 	callback func(...interface{})
 	jobFunc  func(*RenderJob) []interface{}
 	params   []interface{}
+	renderer *Renderer
 }
 
 func (j *RenderJob) execute() {
@@ -19,9 +16,9 @@ func (j *RenderJob) execute() {
 	}
 }
 
-func performJobs() {
+func (r *Renderer) performJobs() {
 	select {
-	case job := <-renderJobs:
+	case job := <-r.renderJobs:
 		job.execute()
 		return
 	default:
@@ -31,64 +28,87 @@ func performJobs() {
 
 // Non-blocking send, this is working on a single go routine with nested sends
 // So this is expected to block sometimes.
-func AddJob(job *RenderJob) bool {
+func (r *Renderer) AddJob(job *RenderJob) bool {
+	job.renderer = r
 	select {
-	case renderJobs <- job:
+	case r.renderJobs <- job:
 		return true
 	default:
 		return false
 	}
 }
 
-func AddJobBlock(ro RenderObject, block func(r RenderObject)) bool {
+func (r *Renderer) AddJobBlock(ro RenderObject, block func(r RenderObject)) bool {
 	jobWrapper := func(job *RenderJob) []interface{} {
 		block((job.params[0]).(RenderObject))
 		return nil
 	}
-	return AddJob(&RenderJob{
+	return r.AddJob(&RenderJob{
 		jobFunc: jobWrapper,
 		params:  []interface{}{ro},
 	})
 }
 
-func CreateDefaultRenderObjectJob(ro *DefaultRenderObject, texture string, elements int, callback func(...interface{})) {
-	AddJob(&RenderJob{
+func (r *Renderer) CreateDefaultRenderObjectJob(ro *DefaultRenderObject, texture string, elements int, callback func(...interface{})) {
+	r.AddJob(&RenderJob{
 		callback: callback,
 		jobFunc:  callCreateDefaultRenderObject,
 		params:   []interface{}{ro, texture, elements},
 	})
 }
 
-func CreateBaseRenderObjectJob(ro *BaseRenderObject, texture string, elements int, callback func(...interface{})) {
-	AddJob(&RenderJob{
+func (r *Renderer) CreateBaseRenderObjectJob(ro *BaseRenderObject, texture string, elements int, callback func(...interface{})) {
+	r.AddJob(&RenderJob{
 		callback: callback,
 		jobFunc:  callCreateBaseRenderObject,
 		params:   []interface{}{ro, texture, elements},
 	})
 }
 
+func (r *Renderer) UpdateBuffersJob(ro RenderObject, callback func(...interface{})) {
+	r.AddJob(&RenderJob{
+		callback: callback,
+		jobFunc:  callUpdateBuffers,
+		params:   []interface{}{ro},
+	})
+}
+
+// Wrappers over the default renderer
+
+func AddJob(job *RenderJob) bool {
+	return defaultRenderer.AddJob(job)
+}
+
+func AddJobBlock(ro RenderObject, block func(r RenderObject)) bool {
+	return defaultRenderer.AddJobBlock(ro, block)
+}
+
+func CreateDefaultRenderObjectJob(ro *DefaultRenderObject, texture string, elements int, callback func(...interface{})) {
+	defaultRenderer.CreateDefaultRenderObjectJob(ro, texture, elements, callback)
+}
+
+func CreateBaseRenderObjectJob(ro *BaseRenderObject, texture string, elements int, callback func(...interface{})) {
+	defaultRenderer.CreateBaseRenderObjectJob(ro, texture, elements, callback)
+}
+
+func UpdateBuffersJob(ro RenderObject, callback func(...interface{})) {
+	defaultRenderer.UpdateBuffersJob(ro, callback)
+}
+
 func callCreateDefaultRenderObject(job *RenderJob) []interface{} {
-	ro := CreateDefaultRenderObject((job.params[1]).(string), (job.params[2]).(int))
+	ro := job.renderer.CreateDefaultRenderObject((job.params[1]).(string), (job.params[2]).(int))
 	*(job.params[0]).(*DefaultRenderObject) = *ro
 	ro.async = true
 	return []interface{}{ro}
 }
 
 func callCreateBaseRenderObject(job *RenderJob) []interface{} {
-	ro := CreateBaseRenderObject((job.params[1]).(string), (job.params[2]).(int))
+	ro := job.renderer.CreateBaseRenderObject((job.params[1]).(string), (job.params[2]).(int))
 	*(job.params[0]).(*BaseRenderObject) = *ro
 	ro.async = true
 	return []interface{}{ro}
 }
 
-func UpdateBuffersJob(ro RenderObject, callback func(...interface{})) {
-	AddJob(&RenderJob{
-		callback: callback,
-		jobFunc:  callUpdateBuffers,
-		params:   []interface{}{ro},
-	})
-}
-
 func callUpdateBuffers(job *RenderJob) []interface{} {
 	ro := job.params[0].(RenderObject)
 	ro.UpdateBuffers()
@@ -2,6 +2,8 @@ package graphics
 
 import (
 	"fmt"
+	"image/color"
+	"math"
 
 	"github.com/lucas-s-work/gopengl2/graphics/opengl"
 )
@@ -16,6 +18,10 @@ type RenderObject interface {
 	Delete()
 	Created() bool
 	GetVAO() opengl.VAO
+	Layer() int
+	SetLayer(int)
+	ZIndex() int
+	SetZIndex(int)
 }
 
 /*
@@ -30,31 +36,40 @@ type BaseRenderObject struct {
 	freeVert                   int
 	vBuff                      *opengl.Buffer
 	tBuff                      *opengl.Buffer
+	cBuff                      *opengl.Buffer
+	rBuff                      *opengl.Buffer
 	ShouldRender               bool
 	shouldWait                 bool
 	waitChan                   chan WaitSignal
 	updated, autoUpdate, async bool
+	layer, zIndex              int
+	deleted                    bool
+	hiddenByNode               bool
+	renderer                   *Renderer
 }
 
-func CreateBaseRenderObject(texture string, elements int) *BaseRenderObject {
-	vao := opengl.CreateDefaultVao(window, texture, elements)
+func (r *Renderer) createBaseRenderObject(texture string, elements int) *BaseRenderObject {
+	vao := opengl.CreateDefaultVao(r.window, texture, elements)
 
-	ro := &BaseRenderObject{
+	return &BaseRenderObject{
 		vao,
 		0,
 		vao.GetBuffer("vert"),
 		vao.GetBuffer("verttexcoord"),
+		nil,
+		nil,
 		true,
 		false,
 		make(chan WaitSignal),
 		false,
 		false,
 		false,
+		LayerWorld,
+		0,
+		false,
+		false,
+		r,
 	}
-
-	renderObjects = append(renderObjects, ro)
-
-	return ro
 }
 
 func (ro *BaseRenderObject) SetVertex(index, x, y, texX, texY int) {
@@ -66,6 +81,44 @@ func (ro *BaseRenderObject) SetVertex(index, x, y, texX, texY int) {
 	ro.vBuff.Elements[i+1] = float32(y)
 	ro.tBuff.Elements[i] = tX
 	ro.tBuff.Elements[i+1] = tY
+	ro.vBuff.MarkChanged()
+	ro.tBuff.MarkChanged()
+
+	ro.updated = true
+}
+
+// Colour components are used as is (not premultiplied), white leaves the texture untouched
+func (ro *BaseRenderObject) SetVertexColor(index int, c color.RGBA) {
+	i := index * 4
+
+	// Created on the first tint
+	if ro.cBuff == nil {
+		ro.cBuff = ro.vao.ColourBuffer()
+	}
+
+	ro.cBuff.Elements[i] = float32(c.R) / 255
+	ro.cBuff.Elements[i+1] = float32(c.G) / 255
+	ro.cBuff.Elements[i+2] = float32(c.B) / 255
+	ro.cBuff.Elements[i+3] = float32(c.A) / 255
+	ro.cBuff.MarkChanged()
+
+	ro.updated = true
+}
+
+// Rotate the vertex by angle radians around (cx, cy), applied before the object transforms
+func (ro *BaseRenderObject) SetVertexRotation(index int, cx, cy, angle float32) {
+	i := index * 4
+
+	// Created on the first rotation
+	if ro.rBuff == nil {
+		ro.rBuff = ro.vao.RotationBuffer()
+	}
+
+	ro.rBuff.Elements[i] = cx
+	ro.rBuff.Elements[i+1] = cy
+	ro.rBuff.Elements[i+2] = float32(math.Cos(float64(angle)))
+	ro.rBuff.Elements[i+3] = float32(math.Sin(float64(angle)))
+	ro.rBuff.MarkChanged()
 
 	ro.updated = true
 }
@@ -78,20 +131,31 @@ func (ro *BaseRenderObject) SetAutoUpdate(update bool) {
 }
 
 func (ro *BaseRenderObject) UpdateBuffers() {
-	ro.vao.BindVao()
-	ro.vao.UpdateBuffers()
+	ro.vao.UpdateChangedBuffers()
 	ro.updated = false
 }
 
 func (ro *BaseRenderObject) PrepRender() {
 	if ro.updated && ro.autoUpdate {
-		ro.vao.BindVao()
-		ro.vao.UpdateBuffers()
+		ro.vao.UpdateChangedBuffers()
 		ro.updated = true
 	}
 	ro.vao.PrepRender()
 }
 
+func (ro *BaseRenderObject) SetVisible(visible bool) {
+	ro.ShouldRender = visible
+}
+
+// Visibility of the scene graph node the object is attached to, kept apart from ShouldRender
+func (ro *BaseRenderObject) SetNodeVisible(visible bool) {
+	ro.hiddenByNode = !visible
+}
+
+func (ro *BaseRenderObject) visible() bool {
+	return ro.ShouldRender && !ro.hiddenByNode
+}
+
 func (ro *BaseRenderObject) SetWait(shouldWait bool) chan WaitSignal {
 	ro.shouldWait = shouldWait
 
@@ -108,20 +172,53 @@ func (ro *BaseRenderObject) Render() {
 		<-ro.waitChan
 	}
 
-	if ro.ShouldRender {
+	if ro.visible() {
 		ro.PrepRender()
 		ro.vao.Render()
 	}
 }
 
+// Unregisters the object and frees its GPU resources, deferred to the end of the frame if
+// called while rendering. Must be called from the opengl thread, use AddJobBlock otherwise.
 func (ro *BaseRenderObject) Delete() {
+	if ro.deleted {
+		return
+	}
+
+	if ro.renderer.rendering {
+		ro.renderer.pendingDeletes = append(ro.renderer.pendingDeletes, ro)
+		return
+	}
+
+	ro.renderer.removeRenderObject(ro)
 	ro.vao.Delete()
+	ro.deleted = true
 }
 
 func (ro *BaseRenderObject) GetVAO() opengl.VAO {
 	return ro.vao
 }
 
+// Draw ordering
+
+func (ro *BaseRenderObject) Layer() int {
+	return ro.layer
+}
+
+func (ro *BaseRenderObject) SetLayer(layer int) {
+	ro.layer = layer
+	ro.renderer.orderDirty = true
+}
+
+func (ro *BaseRenderObject) ZIndex() int {
+	return ro.zIndex
+}
+
+func (ro *BaseRenderObject) SetZIndex(z int) {
+	ro.zIndex = z
+	ro.renderer.orderDirty = true
+}
+
 func (ro *BaseRenderObject) Created() bool {
 	if ro == nil {
 		return false
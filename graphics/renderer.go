@@ -0,0 +1,189 @@
+package graphics
+
+import (
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lucas-s-work/gopengl2/graphics/opengl"
+)
+
+/*
+A Renderer owns a window, the render objects drawn to it, its scene graph and job queue.
+Render objects are added to the renderer that created them and rendered without additional work.
+*/
+
+type Renderer struct {
+	window          *opengl.Window
+	renderObjects   []RenderObject
+	renderJobs      chan *RenderJob
+	root            *Node
+	orderDirty      bool
+	rendering       bool
+	pendingRemovals []RenderObject
+	pendingDeletes  []*BaseRenderObject
+	target          *opengl.Framebuffer
+	timer           frameTimer
+}
+
+func NewRenderer(w *opengl.Window) *Renderer {
+	return &Renderer{
+		window:     w,
+		renderJobs: make(chan *RenderJob, 100),
+		root:       NewNode(nil),
+	}
+}
+
+func (r *Renderer) Window() *opengl.Window {
+	return r.window
+}
+
+// Root of the scene graph, only nodes attached under it are updated
+func (r *Renderer) Root() *Node {
+	return r.root
+}
+
+/*
+Render to a fixed size canvas which is scaled to fit the window, render objects and the
+mouse position then work in the virtual resolution regardless of the window size.
+Must be called from the opengl thread.
+*/
+
+func (r *Renderer) SetVirtualResolution(width, height int, integerScaling bool) {
+	r.window.MakeCurrent()
+	r.ClearVirtualResolution()
+
+	r.target = opengl.CreateFramebuffer(width, height)
+	r.window.SetVirtualResolution(width, height, integerScaling)
+}
+
+func (r *Renderer) ClearVirtualResolution() {
+	if r.target == nil {
+		return
+	}
+
+	r.window.MakeCurrent()
+	r.target.Delete()
+	r.target = nil
+	r.window.ClearVirtualResolution()
+}
+
+// Render object creation
+
+func (r *Renderer) CreateDefaultRenderObject(texture string, elements int) *DefaultRenderObject {
+	ro := &DefaultRenderObject{r.createBaseRenderObject(texture, elements)}
+
+	r.renderObjects = append(r.renderObjects, ro)
+	r.orderDirty = true
+
+	return ro
+}
+
+func (r *Renderer) CreateBaseRenderObject(texture string, elements int) *BaseRenderObject {
+	ro := r.createBaseRenderObject(texture, elements)
+
+	r.renderObjects = append(r.renderObjects, ro)
+	r.orderDirty = true
+
+	return ro
+}
+
+func (r *Renderer) DeleteRenderObjects() {
+	// Delete unregisters each object so iterate over a copy
+	for _, ro := range append([]RenderObject{}, r.renderObjects...) {
+		ro.Delete()
+	}
+}
+
+/*
+Remove unregisters a render object without freeing its GPU resources, it can be
+re-added with Add. Removals requested mid-frame are applied once the frame has been drawn.
+*/
+
+func (r *Renderer) Remove(ro RenderObject) {
+	if r.rendering {
+		r.pendingRemovals = append(r.pendingRemovals, ro)
+		return
+	}
+
+	r.removeRenderObject(ro)
+}
+
+func (r *Renderer) Add(ro RenderObject) {
+	for _, other := range r.renderObjects {
+		if other.GetVAO() == ro.GetVAO() {
+			return
+		}
+	}
+
+	r.renderObjects = append(r.renderObjects, ro)
+	r.orderDirty = true
+}
+
+// Objects are matched by VAO so embedded BaseRenderObjects find their parent object
+func (r *Renderer) removeRenderObject(ro RenderObject) {
+	for i, other := range r.renderObjects {
+		if other.GetVAO() == ro.GetVAO() {
+			r.renderObjects = append(r.renderObjects[:i], r.renderObjects[i+1:]...)
+			return
+		}
+	}
+}
+
+func (r *Renderer) flushRemovals() {
+	for _, ro := range r.pendingRemovals {
+		r.removeRenderObject(ro)
+	}
+	r.pendingRemovals = nil
+
+	for _, ro := range r.pendingDeletes {
+		ro.Delete()
+	}
+	r.pendingDeletes = nil
+}
+
+// Rendering
+
+func (r *Renderer) Render() {
+	r.renderFrame()
+	r.window.PollInput()
+}
+
+// Render without polling input, the Loop polls before each update step instead
+func (r *Renderer) renderFrame() {
+	r.window.MakeCurrent()
+	r.window.UpdateViewport()
+	r.timer.begin()
+
+	//Process job queue
+	jobStart := time.Now()
+	r.performJobs()
+	r.timer.current.Jobs = time.Since(jobStart)
+
+	// Propagate scene graph transforms to the render objects
+	r.root.update(mgl32.Ident3(), true, false)
+
+	r.sortRenderObjects()
+
+	if r.target != nil {
+		r.target.Bind()
+	}
+
+	PrepRender()
+	r.rendering = true
+	for _, obj := range r.renderObjects {
+		obj.Render()
+	}
+	r.rendering = false
+
+	if r.target != nil {
+		// Scale the canvas into the window, the clear leaves the bars black
+		r.target.Unbind()
+		r.window.ResetViewport()
+		PrepRender()
+		r.target.Present(r.window.CanvasRect())
+	}
+
+	r.flushRemovals()
+	r.timer.end()
+	r.window.SwapBuffers()
+}
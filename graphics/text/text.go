@@ -21,6 +21,8 @@ type Text struct {
 	currentTextIndexs []int
 }
 
+const maxLetters = 1000
+
 var (
 	defaultFontLocation = "./resources/sprites/font.png"
 	defaultLetterString = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_"
@@ -42,9 +44,10 @@ func LoadFont(location, letters string) *Font {
 		}
 
 		letterMap[c] = fontCoord{i * width, j * height}
+		i++
 	}
 
-	return &Font{location, letters, letterMap, 16, 16}
+	return &Font{letters, location, letterMap, 16, 16}
 }
 
 func LoadDefaultFont() {
@@ -61,10 +64,10 @@ func CreateText(text string, x, y int, font *Font) Text {
 		panic("font set to nil and default font not loaded.")
 	}
 
-	ro := graphics.CreateDefaultRenderObject(defaultFontLocation, 1000*2)
-	indexs := make([]int, 1000)
+	ro := graphics.CreateDefaultRenderObject(font.fontLocation, maxLetters*2)
+	indexs := make([]int, maxLetters)
 	// Initialize the positions used for the render object
-	for i := 0; i < 1000; i++ {
+	for i := 0; i < maxLetters; i++ {
 		indexs[i] = ro.CreateSquare(0, 0, 0, 0, 0, 0)
 	}
 
@@ -73,37 +76,48 @@ func CreateText(text string, x, y int, font *Font) Text {
 	return Text{font, ro, text, indexs}
 }
 
-// Update the text RO
-func (t Text) UpdateText(text string, x, y int) {
-	t.font.renderText(x, y, text, t.R, t.currentTextIndexs, 100)
+// Update the text RO, false if the render job queue was full and the text is unchanged
+func (t Text) UpdateText(text string, x, y int) bool {
+	return t.font.renderText(x, y, text, t.R, t.currentTextIndexs, 100)
+}
+
+// Lay out and upload the text immediately, must be called from the opengl thread e.g in a job
+func (t Text) SetText(text string, x, y int) {
+	t.font.layoutText(x, y, text, t.R, t.currentTextIndexs, 100)
+	t.R.UpdateBuffers()
 }
 
-func (f Font) renderText(x, y int, text string, ro *graphics.DefaultRenderObject, indexs []int, wrap int) {
+func (f Font) renderText(x, y int, text string, ro *graphics.DefaultRenderObject, indexs []int, wrap int) bool {
 	// Perform this job asynchronously
-	graphics.AddJobBlock(ro, func(r graphics.RenderObject) {
-		ro := r.(*graphics.DefaultRenderObject)
-		// remove all previous text
-		for _, index := range indexs {
-			ro.ModifyRect(index, 0, 0, 0, 0, 0, 0, 0, 0)
-		}
+	return graphics.AddJobBlock(ro, func(r graphics.RenderObject) {
+		f.layoutText(x, y, text, r.(*graphics.DefaultRenderObject), indexs, wrap)
+		r.UpdateBuffers()
+	})
+}
 
-		j := 0
-		k := 0
-		for i, c := range text {
-			k++
-			if k > wrap {
-				k = 0
-				j++
-			}
+// Position the glyphs, must be called from the opengl thread
+func (f Font) layoutText(x, y int, text string, ro *graphics.DefaultRenderObject, indexs []int, wrap int) {
+	// remove all previous text
+	for _, index := range indexs {
+		ro.ModifyRect(index, 0, 0, 0, 0, 0, 0, 0, 0)
+	}
 
-			coord := f.letterMap[c]
+	j := 0
+	k := 0
+	for i, c := range text {
+		k++
+		if k > wrap {
+			k = 0
+			j++
+		}
 
-			if &coord == nil {
-				panic("Unable to find coord")
-			}
+		coord := f.letterMap[c]
 
-			// Add current text with letter wrapping
-			ro.ModifyRect(indexs[i], x+k*f.letterWidth, y+j*f.letterHeight, f.letterWidth, f.letterHeight, coord.x, coord.y, f.letterWidth, f.letterHeight)
+		if &coord == nil {
+			panic("Unable to find coord")
 		}
-	})
+
+		// Add current text with letter wrapping
+		ro.ModifyRect(indexs[i], x+k*f.letterWidth, y+j*f.letterHeight, f.letterWidth, f.letterHeight, coord.x, coord.y, f.letterWidth, f.letterHeight)
+	}
 }
@@ -0,0 +1,371 @@
+package text
+
+import (
+	"image/color"
+	"sync"
+	"time"
+
+	"github.com/lucas-s-work/gopengl2/graphics"
+	"github.com/lucas-s-work/gopengl2/graphics/opengl"
+)
+
+/*
+Single line editable text rendered through a Text. The field subscribes to the window's events,
+typing inserts at the cursor, shift extends the selection and ctrl handles the usual shortcuts
+(select all, copy, cut and paste through the window clipboard).
+*/
+
+var (
+	SelectionColor = color.RGBA{255, 220, 0, 255}
+	cursorGlyph    = '_'
+)
+
+// Delay before queueing the render job again when the renderer's queue is full
+const renderRetry = 10 * time.Millisecond
+
+type TextField struct {
+	text        Text
+	window      *opengl.Window
+	x, y        int
+	mutex       sync.Mutex
+	value       []rune
+	cursor      int
+	anchor      int // Other end of the selection, equal to cursor when nothing is selected
+	focused     bool
+	MaxLength   int
+	OnSubmit    func(string)
+	unsubscribe func()
+	queued      bool // A render job is waiting to run
+	deleted     bool
+}
+
+func CreateTextField(window *opengl.Window, x, y int, font *Font) *TextField {
+	field := &TextField{
+		text:      CreateText("", x, y, font),
+		window:    window,
+		x:         x,
+		y:         y,
+		MaxLength: maxLetters - 1, // Keep one glyph for the cursor
+	}
+	field.unsubscribe = window.Subscribe(field.HandleEvent)
+
+	return field
+}
+
+// Must be called from the opengl thread, as for the render object
+func (f *TextField) Delete() {
+	f.unsubscribe()
+
+	f.mutex.Lock()
+	f.deleted = true
+	f.mutex.Unlock()
+
+	f.text.R.Delete()
+}
+
+// Value and focus
+
+func (f *TextField) Value() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return string(f.value)
+}
+
+func (f *TextField) SetValue(value string) {
+	f.mutex.Lock()
+	f.value = []rune(value)
+	if limit := f.maxLength(); len(f.value) > limit {
+		f.value = f.value[:limit]
+	}
+	f.cursor = len(f.value)
+	f.anchor = f.cursor
+	f.mutex.Unlock()
+	f.render()
+}
+
+func (f *TextField) Focus() {
+	f.mutex.Lock()
+	f.focused = true
+	f.mutex.Unlock()
+	f.render()
+}
+
+func (f *TextField) Blur() {
+	f.mutex.Lock()
+	f.focused = false
+	f.anchor = f.cursor
+	f.mutex.Unlock()
+	f.render()
+}
+
+func (f *TextField) Focused() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.focused
+}
+
+func (f *TextField) Selection() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	start, end := f.selection()
+	return string(f.value[start:end])
+}
+
+// Event handling
+
+func (f *TextField) HandleEvent(ev opengl.Event) {
+	f.mutex.Lock()
+	if !f.focused {
+		f.mutex.Unlock()
+		return
+	}
+
+	var submit func(string)
+	changed := false
+	switch ev.Type {
+	case opengl.EventChar:
+		// Characters typed with ctrl or super held are shortcuts, not text
+		if ev.Mods&(opengl.ModControl|opengl.ModSuper) == 0 {
+			f.insert([]rune{ev.Char})
+			changed = true
+		}
+	case opengl.EventKey:
+		if ev.Action != opengl.ActionRelease {
+			changed = f.handleKey(ev.Key, ev.Mods)
+			if ev.Key == opengl.KeyEnter || ev.Key == opengl.KeyKPEnter {
+				submit = f.OnSubmit
+			}
+		}
+	}
+	value := string(f.value)
+	f.mutex.Unlock()
+
+	if changed {
+		f.render()
+	}
+	if submit != nil {
+		submit(value)
+	}
+}
+
+func (f *TextField) handleKey(key opengl.Key, mods opengl.ModifierKey) bool {
+	shift := mods&opengl.ModShift != 0
+	shortcut := mods&(opengl.ModControl|opengl.ModSuper) != 0
+
+	switch key {
+	case opengl.KeyBackspace:
+		if !f.deleteSelection() && f.cursor > 0 {
+			f.value = append(f.value[:f.cursor-1], f.value[f.cursor:]...)
+			f.cursor--
+			f.anchor = f.cursor
+		}
+	case opengl.KeyDelete:
+		if !f.deleteSelection() && f.cursor < len(f.value) {
+			f.value = append(f.value[:f.cursor], f.value[f.cursor+1:]...)
+		}
+	case opengl.KeyLeft:
+		if shortcut {
+			f.moveCursor(f.wordStart(f.cursor), shift)
+		} else {
+			f.moveCursor(f.cursor-1, shift)
+		}
+	case opengl.KeyRight:
+		if shortcut {
+			f.moveCursor(f.wordEnd(f.cursor), shift)
+		} else {
+			f.moveCursor(f.cursor+1, shift)
+		}
+	case opengl.KeyHome:
+		f.moveCursor(0, shift)
+	case opengl.KeyEnd:
+		f.moveCursor(len(f.value), shift)
+	case opengl.KeyA:
+		if !shortcut {
+			return false
+		}
+		f.anchor = 0
+		f.cursor = len(f.value)
+	case opengl.KeyC, opengl.KeyX:
+		if !shortcut {
+			return false
+		}
+		start, end := f.selection()
+		if start == end {
+			return false
+		}
+		f.window.SetClipboard(string(f.value[start:end]))
+		if key == opengl.KeyX {
+			f.deleteSelection()
+		}
+	case opengl.KeyV:
+		if !shortcut {
+			return false
+		}
+		f.insert([]rune(f.window.Clipboard()))
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Editing helpers, called with the mutex held
+
+func (f *TextField) selection() (int, int) {
+	if f.anchor < f.cursor {
+		return f.anchor, f.cursor
+	}
+
+	return f.cursor, f.anchor
+}
+
+func (f *TextField) deleteSelection() bool {
+	start, end := f.selection()
+	if start == end {
+		return false
+	}
+
+	f.value = append(f.value[:start], f.value[end:]...)
+	f.cursor = start
+	f.anchor = start
+
+	return true
+}
+
+// MaxLength capped to the glyphs available, 0 or less means no limit beyond that
+func (f *TextField) maxLength() int {
+	if f.MaxLength > 0 && f.MaxLength < maxLetters-1 {
+		return f.MaxLength
+	}
+
+	return maxLetters - 1
+}
+
+func (f *TextField) insert(runes []rune) {
+	f.deleteSelection()
+
+	if limit := f.maxLength(); len(f.value)+len(runes) > limit {
+		remaining := limit - len(f.value)
+		if remaining < 0 {
+			remaining = 0
+		}
+		runes = runes[:remaining]
+	}
+
+	value := append([]rune{}, f.value[:f.cursor]...)
+	value = append(value, runes...)
+	f.value = append(value, f.value[f.cursor:]...)
+	f.cursor += len(runes)
+	f.anchor = f.cursor
+}
+
+func (f *TextField) moveCursor(position int, extend bool) {
+	// Without shift a selection collapses to the side moved towards
+	start, end := f.selection()
+	if !extend && start != end {
+		if position < f.cursor {
+			position = start
+		} else {
+			position = end
+		}
+	}
+
+	if position < 0 {
+		position = 0
+	}
+	if position > len(f.value) {
+		position = len(f.value)
+	}
+
+	f.cursor = position
+	if !extend {
+		f.anchor = position
+	}
+}
+
+func (f *TextField) wordStart(position int) int {
+	for position > 0 && f.value[position-1] == ' ' {
+		position--
+	}
+	for position > 0 && f.value[position-1] != ' ' {
+		position--
+	}
+
+	return position
+}
+
+func (f *TextField) wordEnd(position int) int {
+	for position < len(f.value) && f.value[position] == ' ' {
+		position++
+	}
+	for position < len(f.value) && f.value[position] != ' ' {
+		position++
+	}
+
+	return position
+}
+
+// Rendering
+
+// The job draws the field as it is when the job runs, so changes made while one is queued share it
+func (f *TextField) render() {
+	f.mutex.Lock()
+	if f.queued || f.deleted {
+		f.mutex.Unlock()
+		return
+	}
+	f.queued = true
+	f.mutex.Unlock()
+
+	if graphics.AddJobBlock(f.text.R, f.renderJob) {
+		return
+	}
+
+	// The queue was full, try again rather than leave the field stale
+	f.mutex.Lock()
+	f.queued = false
+	f.mutex.Unlock()
+	time.AfterFunc(renderRetry, f.render)
+}
+
+func (f *TextField) renderJob(r graphics.RenderObject) {
+	f.mutex.Lock()
+	value := append([]rune{}, f.value...)
+	cursor := f.cursor
+	start, end := f.selection()
+	focused := f.focused
+	deleted := f.deleted
+	f.queued = false
+	f.mutex.Unlock()
+
+	if deleted {
+		return
+	}
+
+	font := f.text.font
+	indexs := f.text.currentTextIndexs
+	x, y := f.x, f.y
+
+	ro := r.(*graphics.DefaultRenderObject)
+	for _, index := range indexs {
+		ro.ModifyRect(index, 0, 0, 0, 0, 0, 0, 0, 0)
+		ro.SetRectColor(index, color.RGBA{255, 255, 255, 255})
+	}
+
+	for i, c := range value {
+		coord := font.letterMap[c]
+		ro.ModifyRect(indexs[i], x+i*font.letterWidth, y, font.letterWidth, font.letterHeight, coord.x, coord.y, font.letterWidth, font.letterHeight)
+
+		if i >= start && i < end {
+			ro.SetRectColor(indexs[i], SelectionColor)
+		}
+	}
+
+	// The last glyph is reserved for the cursor
+	if focused {
+		coord := font.letterMap[cursorGlyph]
+		ro.ModifyRect(indexs[len(indexs)-1], x+cursor*font.letterWidth, y, font.letterWidth, font.letterHeight, coord.x, coord.y, font.letterWidth, font.letterHeight)
+	}
+
+	ro.UpdateBuffers()
+}
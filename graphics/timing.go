@@ -0,0 +1,136 @@
+package graphics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lucas-s-work/gopengl2/graphics/opengl"
+)
+
+/*
+Per frame timing of Render. CPU times are measured around each stage, the GPU time uses timer
+queries so lags a few frames behind. Frame times are kept over a rolling window for FPS and
+percentile statistics.
+*/
+
+const frameHistory = 120
+
+type FrameTiming struct {
+	Frame    time.Duration // Time since the previous Render started
+	CPU      time.Duration // Time spent in Render excluding the buffer swap
+	Jobs     time.Duration
+	Culling  time.Duration
+	Uniforms time.Duration // Buffer and uniform updates before drawing
+	Draw     time.Duration // Submitting draw calls, not their execution
+	GPU      time.Duration
+	GPUValid bool
+}
+
+type FrameStatistics struct {
+	Last          FrameTiming
+	FPS           float64
+	Average       time.Duration
+	P50, P95, P99 time.Duration
+	Max           time.Duration
+}
+
+type frameTimer struct {
+	mutex    sync.Mutex
+	current  FrameTiming
+	last     FrameTiming
+	start    time.Time
+	history  [frameHistory]time.Duration
+	count    int
+	next     int
+	gpuTimer *opengl.GPUTimer
+}
+
+// Called at the start of Render in the opengl thread
+func (t *frameTimer) begin() {
+	if t.gpuTimer == nil {
+		t.gpuTimer = opengl.CreateGPUTimer()
+	}
+
+	now := time.Now()
+	t.current = FrameTiming{}
+	if !t.start.IsZero() {
+		t.current.Frame = now.Sub(t.start)
+	}
+	t.start = now
+
+	t.gpuTimer.Begin()
+}
+
+func (t *frameTimer) end() {
+	t.gpuTimer.End()
+	t.current.CPU = time.Since(t.start)
+	t.current.GPU, t.current.GPUValid = t.gpuTimer.Result()
+
+	t.mutex.Lock()
+	t.last = t.current
+	if t.current.Frame > 0 {
+		t.history[t.next] = t.current.Frame
+		t.next = (t.next + 1) % frameHistory
+		if t.count < frameHistory {
+			t.count++
+		}
+	}
+	t.mutex.Unlock()
+}
+
+// Stage timings for a single render object, called while rendering
+func (t *frameTimer) addObject(uniforms, culling, draw time.Duration) {
+	t.current.Uniforms += uniforms
+	t.current.Culling += culling
+	t.current.Draw += draw
+}
+
+func (t *frameTimer) stats() FrameStatistics {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := FrameStatistics{Last: t.last}
+	if t.count == 0 {
+		return stats
+	}
+
+	frames := make([]time.Duration, t.count)
+	copy(frames, t.history[:t.count])
+	sort.Slice(frames, func(i, j int) bool { return frames[i] < frames[j] })
+
+	var total time.Duration
+	for _, f := range frames {
+		total += f
+	}
+
+	stats.Average = total / time.Duration(len(frames))
+	stats.FPS = float64(len(frames)) / total.Seconds()
+	stats.P50 = percentile(frames, 0.50)
+	stats.P95 = percentile(frames, 0.95)
+	stats.P99 = percentile(frames, 0.99)
+	stats.Max = frames[len(frames)-1]
+
+	return stats
+}
+
+// Nearest rank on sorted frame times
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	index := int(p*float64(len(sorted))+0.5) - 1
+	if index < 0 {
+		index = 0
+	} else if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+
+	return sorted[index]
+}
+
+// Timing of the last rendered frame and statistics over recent frames, safe from any goroutine
+func (r *Renderer) FrameStats() FrameStatistics {
+	return r.timer.stats()
+}
+
+func FrameStats() FrameStatistics {
+	return defaultRenderer.FrameStats()
+}
@@ -0,0 +1,295 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/lucas-s-work/gopengl2/graphics/opengl"
+)
+
+/*
+Named actions ("jump", "move_x") bound to keys, mouse buttons and gamepad buttons or axes.
+Call Update once per frame after the window's PollInput, at the start of update when using the
+graphics Loop, then query actions regardless of the device that triggered them.
+*/
+
+type BindingType string
+
+const (
+	BindKey           BindingType = "key"
+	BindMouse         BindingType = "mouse"
+	BindGamepadButton BindingType = "gamepad_button"
+	BindGamepadAxis   BindingType = "gamepad_axis"
+)
+
+// An axis binding counts as held once its scaled value passes this
+const AxisThreshold = 0.5
+
+/*
+Name is a key name, mouse button, gamepad button or gamepad axis name depending on the type.
+Scale is the analog value of a held button, or multiplies an axis, zero is treated as 1.
+*/
+
+type Binding struct {
+	Type  BindingType `json:"type"`
+	Name  string      `json:"name"`
+	Scale float32     `json:"scale,omitempty"`
+}
+
+func Key(name string) Binding {
+	return Binding{Type: BindKey, Name: name}
+}
+
+func Mouse(name string) Binding {
+	return Binding{Type: BindMouse, Name: name}
+}
+
+func GamepadButton(name string) Binding {
+	return Binding{Type: BindGamepadButton, Name: name}
+}
+
+func GamepadAxis(name string) Binding {
+	return Binding{Type: BindGamepadAxis, Name: name}
+}
+
+func (b Binding) WithScale(scale float32) Binding {
+	b.Scale = scale
+	return b
+}
+
+func (b Binding) scale() float32 {
+	if b.Scale == 0 {
+		return 1
+	}
+
+	return b.Scale
+}
+
+func (b Binding) validate() error {
+	var ok bool
+	switch b.Type {
+	case BindKey:
+		_, ok = opengl.KeyFromName(b.Name)
+	case BindMouse:
+		_, ok = mouseButtonNames[b.Name]
+	case BindGamepadButton:
+		_, ok = gamepadButtonNames[b.Name]
+	case BindGamepadAxis:
+		_, ok = gamepadAxisNames[b.Name]
+	default:
+		return fmt.Errorf("unknown binding type %q", b.Type)
+	}
+
+	if !ok {
+		return fmt.Errorf("unknown %s %q", b.Type, b.Name)
+	}
+
+	return nil
+}
+
+type actionState struct {
+	held, pressed, released bool
+	value                   float32
+}
+
+type ActionMap struct {
+	mutex    sync.Mutex
+	window   *opengl.Window
+	Joystick opengl.Joystick // Gamepad used by gamepad bindings
+	bindings map[string][]Binding
+	state    map[string]actionState
+}
+
+func NewActionMap(window *opengl.Window) *ActionMap {
+	return &ActionMap{
+		window:   window,
+		bindings: make(map[string][]Binding),
+		state:    make(map[string]actionState),
+	}
+}
+
+// Binding
+
+// Panics on an unknown name, bindings from files are checked by Load instead
+func (m *ActionMap) Bind(action string, bindings ...Binding) {
+	for _, b := range bindings {
+		if err := b.validate(); err != nil {
+			panic(err)
+		}
+	}
+
+	m.mutex.Lock()
+	m.bindings[action] = append(m.bindings[action], bindings...)
+	m.mutex.Unlock()
+}
+
+func (m *ActionMap) Unbind(action string) {
+	m.mutex.Lock()
+	delete(m.bindings, action)
+	delete(m.state, action)
+	m.mutex.Unlock()
+}
+
+func (m *ActionMap) Bindings(action string) []Binding {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]Binding{}, m.bindings[action]...)
+}
+
+func (m *ActionMap) Actions() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	actions := make([]string, 0, len(m.bindings))
+	for action := range m.bindings {
+		actions = append(actions, action)
+	}
+
+	return actions
+}
+
+// Saving and loading, as a JSON object of action names to lists of bindings
+
+func (m *ActionMap) Save(w io.Writer) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m.bindings)
+}
+
+// Replaces all bindings, nothing is changed if any binding is invalid
+func (m *ActionMap) Load(r io.Reader) error {
+	bindings := make(map[string][]Binding)
+	if err := json.NewDecoder(r).Decode(&bindings); err != nil {
+		return err
+	}
+
+	for action, list := range bindings {
+		for _, b := range list {
+			if err := b.validate(); err != nil {
+				return fmt.Errorf("action %q: %v", action, err)
+			}
+		}
+	}
+
+	m.mutex.Lock()
+	m.bindings = bindings
+	m.state = make(map[string]actionState)
+	m.mutex.Unlock()
+
+	return nil
+}
+
+func (m *ActionMap) SaveFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.Save(file)
+}
+
+func (m *ActionMap) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.Load(file)
+}
+
+// Per frame update
+
+func (m *ActionMap) Update() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for action, bindings := range m.bindings {
+		previous := m.state[action]
+		var state actionState
+		tapped := false
+
+		for _, b := range bindings {
+			held, pressed, released, value := m.read(b)
+			state.held = state.held || held
+			state.value += value
+
+			// Pressed and released within one poll, the binding is never seen held
+			tapped = tapped || (pressed && released && !held)
+		}
+
+		// Edges come from the action as a whole so a second binding going down or up while
+		// another is held doesn't press or release it again, axes have no edges of their own
+		state.pressed = !previous.held && (state.held || tapped)
+		state.released = !state.held && (previous.held || tapped)
+
+		if state.value > 1 {
+			state.value = 1
+		} else if state.value < -1 {
+			state.value = -1
+		}
+
+		m.state[action] = state
+	}
+}
+
+func (m *ActionMap) read(b Binding) (held, pressed, released bool, value float32) {
+	w := m.window
+
+	switch b.Type {
+	case BindKey:
+		key, _ := opengl.KeyFromName(b.Name)
+		held, pressed, released = w.KeyHeld(key), w.KeyPressed(key), w.KeyReleased(key)
+	case BindMouse:
+		button := mouseButtonNames[b.Name]
+		held, pressed, released = w.MouseHeld(button), w.MousePressed(button), w.MouseReleased(button)
+	case BindGamepadButton:
+		button := gamepadButtonNames[b.Name]
+		held = w.GamepadButtonHeld(m.Joystick, button)
+		pressed = w.GamepadButtonPressed(m.Joystick, button)
+		released = w.GamepadButtonReleased(m.Joystick, button)
+	case BindGamepadAxis:
+		value = w.GamepadAxis(m.Joystick, gamepadAxisNames[b.Name]) * b.scale()
+		return value > AxisThreshold, false, false, value
+	}
+
+	if held {
+		value = b.scale()
+	}
+
+	return held, pressed, released, value
+}
+
+// Queries, as of the last Update
+
+func (m *ActionMap) Held(action string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.state[action].held
+}
+
+func (m *ActionMap) Pressed(action string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.state[action].pressed
+}
+
+func (m *ActionMap) Released(action string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.state[action].released
+}
+
+// Sum of every binding's value clamped to -1 to 1, held buttons contribute their scale
+func (m *ActionMap) Value(action string) float32 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.state[action].value
+}
@@ -0,0 +1,45 @@
+package input
+
+import "github.com/lucas-s-work/gopengl2/graphics/opengl"
+
+/*
+Names used for bindings so saved bindings stay readable, keys use the names from opengl.
+*/
+
+var (
+	mouseButtonNames = map[string]opengl.MouseButton{
+		"left":    opengl.MouseButtonLeft,
+		"right":   opengl.MouseButtonRight,
+		"middle":  opengl.MouseButtonMiddle,
+		"button4": opengl.MouseButton4,
+		"button5": opengl.MouseButton5,
+		"button6": opengl.MouseButton6,
+		"button7": opengl.MouseButton7,
+		"button8": opengl.MouseButton8,
+	}
+	gamepadButtonNames = map[string]opengl.GamepadButton{
+		"a":            opengl.ButtonA,
+		"b":            opengl.ButtonB,
+		"x":            opengl.ButtonX,
+		"y":            opengl.ButtonY,
+		"left_bumper":  opengl.ButtonLeftBumper,
+		"right_bumper": opengl.ButtonRightBumper,
+		"back":         opengl.ButtonBack,
+		"start":        opengl.ButtonStart,
+		"guide":        opengl.ButtonGuide,
+		"left_thumb":   opengl.ButtonLeftThumb,
+		"right_thumb":  opengl.ButtonRightThumb,
+		"dpad_up":      opengl.ButtonDpadUp,
+		"dpad_right":   opengl.ButtonDpadRight,
+		"dpad_down":    opengl.ButtonDpadDown,
+		"dpad_left":    opengl.ButtonDpadLeft,
+	}
+	gamepadAxisNames = map[string]opengl.GamepadAxis{
+		"left_x":        opengl.AxisLeftX,
+		"left_y":        opengl.AxisLeftY,
+		"right_x":       opengl.AxisRightX,
+		"right_y":       opengl.AxisRightY,
+		"left_trigger":  opengl.AxisLeftTrigger,
+		"right_trigger": opengl.AxisRightTrigger,
+	}
+)
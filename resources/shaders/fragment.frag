@@ -3,6 +3,7 @@ uniform sampler2D tex;
 
 out vec4 frag_colour;
 in vec2 fragtexcoord;
+in vec4 fragcolour;
 void main(){
-    frag_colour=texture(tex, fragtexcoord);
+    frag_colour=texture(tex, fragtexcoord)*fragcolour;
 }
\ No newline at end of file
@@ -0,0 +1,9 @@
+#version 410
+out vec2 fragtexcoord;
+void main(){
+    // Fullscreen quad as a triangle strip from the vertex id, no buffers needed
+    vec2 pos=vec2(gl_VertexID&1,gl_VertexID>>1);
+    fragtexcoord=pos;
+    
+    gl_Position=vec4(pos*2.-1.,0.,1.);
+}
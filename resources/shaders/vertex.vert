@@ -2,43 +2,35 @@
 in vec2 vert;
 in vec4 rotgroup;
 in vec2 verttexcoord;
+in vec4 vertcolour;
 
-//Translation, window dimension scaling, rotation
-uniform vec2 trans;
-uniform mat2 dim;
-uniform mat2 rot;
-uniform vec2 rotcenter;
-
-// Camera and zoom
-uniform float zoom;
-uniform vec2 cam;
+// Object transform, camera and pixel to screen projection
+uniform mat3 model;
+uniform mat3 view;
+uniform mat3 projection;
 
 out vec2 fragtexcoord;
+out vec4 fragcolour;
 void main(){
-    // Set tex coords for frag shader
+    // Set tex coords and tint for frag shader
     fragtexcoord=verttexcoord;
+    fragcolour=vertcolour;
     vec2 pos=vert;
     
     //Apply rotgroup rotation first, we want local changes then global changes to each vertex
-    // vec2 rotcenter=vec2(rotgroup.x,rotgroup.y);
-    // pos-=rotcenter;
-    
-    // mat2 rotmat=mat2(
-        //     rotgroup.z,rotgroup.w,
-        //     -rotgroup.w,rotgroup.z
-    // );
-    // pos=rotmat*pos;
+    vec2 groupcenter=vec2(rotgroup.x,rotgroup.y);
+    pos-=groupcenter;
     
-    // pos+=rotcenter;
+    mat2 rotmat=mat2(
+        rotgroup.z,rotgroup.w,
+        -rotgroup.w,rotgroup.z
+    );
+    pos=rotmat*pos;
     
-    // Apply uniform rotation
-    pos=pos-rotcenter;
-    pos=rot*pos;
-    pos=pos+rotcenter;
+    pos+=groupcenter;
     
-    // Apply screen scaling from pixel coordinates
-    vec2 I=vec2(1,1);
-    pos=zoom*(dim*(pos+trans-cam))-I;
+    // Apply the model, view and projection transforms
+    vec3 screen=projection*view*model*vec3(pos,1.);
     
-    gl_Position=vec4(pos,0.,1.);
-}
\ No newline at end of file
+    gl_Position=vec4(screen.xy,0.,1.);
+}